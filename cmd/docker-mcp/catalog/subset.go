@@ -0,0 +1,87 @@
+package catalog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/yq"
+)
+
+// Subset writes a catalog file with only the given servers of a catalog.
+// Their definitions are copied as is, along with the members of the bundles.
+func Subset(catalogName string, serverNames []string, outputPath string) error {
+	if len(serverNames) == 0 {
+		return fmt.Errorf("at least one server is required")
+	}
+
+	content, err := ReadCatalogFile(catalogName)
+	if err != nil {
+		return fmt.Errorf("failed to read catalog %q: %w", catalogName, err)
+	}
+
+	subset, names, err := subsetCatalog(content, serverNames)
+	if err != nil {
+		return fmt.Errorf("catalog %q: %w", catalogName, err)
+	}
+
+	// Create output directory if it doesn't exist
+	outputDir := filepath.Dir(outputPath)
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	if err := os.WriteFile(outputPath, subset, 0o644); err != nil {
+		return fmt.Errorf("failed to write catalog file: %w", err)
+	}
+
+	fmt.Printf("%d server(s) of catalog '%s' written to '%s': %s\n", len(names), catalogName, outputPath, strings.Join(names, ", "))
+	return nil
+}
+
+// subsetCatalog keeps only the given servers, and the members of the given bundles, in a catalog.
+// Everything else in the catalog, e.g. the shared $defs, is preserved.
+// It returns the new catalog and the sorted names of the servers it contains.
+func subsetCatalog(yamlData []byte, serverNames []string) ([]byte, []string, error) {
+	parsed, err := catalog.Parse(yamlData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse catalog: %w", err)
+	}
+
+	var names []string
+	for _, serverName := range serverNames {
+		server, found := parsed.Servers[serverName]
+		if !found {
+			return nil, nil, fmt.Errorf("server %q not found", serverName)
+		}
+
+		names = append(names, serverName)
+		if server.IsBundle() {
+			for _, member := range server.Members {
+				if _, found := parsed.Servers[member]; !found {
+					return nil, nil, fmt.Errorf("server %q of bundle %q not found", member, serverName)
+				}
+			}
+			names = append(names, server.Members...)
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	var conditions []string
+	for _, name := range names {
+		conditions = append(conditions, ".key == "+strconv.Quote(name))
+	}
+	query := fmt.Sprintf(`.registry |= with_entries(select(%s))`, strings.Join(conditions, " or "))
+
+	subset, err := yq.Evaluate(query, yamlData, yq.NewYamlDecoder(), yq.NewYamlEncoder())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return subset, names, nil
+}
@@ -0,0 +1,89 @@
+package catalog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+const subsetTestCatalog = `name: team
+displayName: Team Catalog
+$defs:
+  port:
+    type: integer
+registry:
+  github:
+    image: mcp/github
+    secrets:
+      - name: github.token
+        env: GITHUB_TOKEN
+  postgres:
+    image: mcp/postgres
+  redis:
+    image: mcp/redis
+  data-stack:
+    type: bundle
+    members: [postgres, redis]
+`
+
+func TestSubsetCatalog(t *testing.T) {
+	subset, names, err := subsetCatalog([]byte(subsetTestCatalog), []string{"github"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"github"}, names)
+
+	parsed, err := catalog.Parse(subset)
+	require.NoError(t, err)
+	assert.Len(t, parsed.Servers, 1)
+	assert.Equal(t, "mcp/github", parsed.Servers["github"].Image)
+	assert.Equal(t, []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}}, parsed.Servers["github"].Secrets)
+	assert.Contains(t, parsed.Defs, "port")
+	assert.Contains(t, string(subset), "displayName: Team Catalog")
+}
+
+func TestSubsetCatalogBundle(t *testing.T) {
+	subset, names, err := subsetCatalog([]byte(subsetTestCatalog), []string{"data-stack", "postgres"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"data-stack", "postgres", "redis"}, names)
+
+	parsed, err := catalog.Parse(subset)
+	require.NoError(t, err)
+	assert.Len(t, parsed.Servers, 3)
+	assert.NotContains(t, parsed.Servers, "github")
+}
+
+func TestSubsetCatalogUnknownServer(t *testing.T) {
+	_, _, err := subsetCatalog([]byte(subsetTestCatalog), []string{"github", "unknown"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"unknown"`)
+}
+
+func TestSubsetCatalogUnknownBundleMember(t *testing.T) {
+	_, _, err := subsetCatalog([]byte(`registry:
+  data-stack:
+    type: bundle
+    members: [postgres, unknown]
+  postgres:
+    image: mcp/postgres
+`), []string{"data-stack"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `server "unknown" of bundle "data-stack" not found`)
+}
+
+func TestSubsetCatalogQuotesNames(t *testing.T) {
+	subset, names, err := subsetCatalog([]byte(`registry:
+  'say "hi"':
+    image: mcp/hi
+  other:
+    image: mcp/other
+`), []string{`say "hi"`})
+	require.NoError(t, err)
+	assert.Equal(t, []string{`say "hi"`}, names)
+
+	parsed, err := catalog.Parse(subset)
+	require.NoError(t, err)
+	assert.Len(t, parsed.Servers, 1)
+	assert.Contains(t, parsed.Servers, `say "hi"`)
+}
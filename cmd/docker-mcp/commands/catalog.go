@@ -23,6 +23,7 @@ func catalogCommand(dockerCli command.Cli) *cobra.Command {
 	cmd.AddCommand(bootstrapCatalogCommand())
 	cmd.AddCommand(importCatalogCommand())
 	cmd.AddCommand(exportCatalogCommand())
+	cmd.AddCommand(subsetCatalogCommand())
 	cmd.AddCommand(lsCatalogCommand(dockerCli))
 	cmd.AddCommand(rmCatalogCommand())
 	cmd.AddCommand(updateCatalogCommand(dockerCli))
@@ -86,6 +87,29 @@ cannot be exported as it is managed by Docker.`,
 	}
 }
 
+func subsetCatalogCommand() *cobra.Command {
+	var opts struct {
+		Servers []string
+	}
+	cmd := &cobra.Command{
+		Use:   "subset <catalog-name> <file-path>",
+		Short: "Write a catalog file with only some of a catalog's servers",
+		Long: `Write a catalog file containing only the given servers of a catalog, e.g. the ones found
+with mcp-find, to ship a minimal catalog to another environment. The server definitions are
+copied as is and the members of the bundles are included.`,
+		Args: cobra.ExactArgs(2),
+		Example: `  # Write the github and slack servers of the Docker catalog to a file
+  docker mcp catalog subset docker-mcp ./minimal-catalog.yaml --server github --server slack`,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return catalog.Subset(args[0], opts.Servers, args[1])
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringSliceVar(&opts.Servers, "server", nil, "Name of a server to keep (can be repeated)")
+	_ = cmd.MarkFlagRequired("server")
+	return cmd
+}
+
 func lsCatalogCommand(dockerCli command.Cli) *cobra.Command {
 	var opts struct {
 		Format catalog.Format
@@ -1,18 +1,25 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"os"
+	"slices"
+	"strings"
 
+	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/backup"
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
 	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
 )
 
-func configCommand(docker docker.Client) *cobra.Command {
+func configCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage the configuration",
@@ -50,6 +57,8 @@ func configCommand(docker docker.Client) *cobra.Command {
 		},
 	})
 
+	cmd.AddCommand(configShowCommand(docker, dockerCli))
+
 	cmd.AddCommand(&cobra.Command{
 		Use:    "dump",
 		Short:  "Dump the whole configuration",
@@ -94,3 +103,110 @@ func configCommand(docker docker.Client) *cobra.Command {
 
 	return cmd
 }
+
+func configShowCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
+	// Same files as the on-host gateway. Nothing is started.
+	options := gateway.Config{
+		CatalogPath:  []string{catalog.DockerCatalogFilename},
+		RegistryPath: []string{"registry.yaml"},
+		ConfigPath:   []string{"config.yaml"},
+		ToolsPath:    []string{"tools.yaml"},
+		SecretsPath:  "secrets.env:docker-desktop",
+	}
+	var additionalCatalogs []string
+	var additionalRegistries []string
+	var additionalConfigs []string
+	var format string
+	var validate bool
+
+	cmd := &cobra.Command{
+		Use:   "show [server...]",
+		Short: "Show the effective configuration of the enabled servers, as resolved by the gateway",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "list" && format != "json" {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+
+			options.ToolNamePrefix = options.ToolNamePrefix || isToolNamePrefixFeatureEnabled(dockerCli)
+			applyConfigurationPaths(dockerCli, &options, additionalCatalogs, additionalRegistries, additionalConfigs)
+
+			report, err := gateway.NewGateway(options, docker).ShowConfig(cmd.Context(), args, validate)
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				buf, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+			} else {
+				printConfigReport(cmd.OutOrStdout(), report)
+			}
+
+			if len(report.Issues) > 0 {
+				return fmt.Errorf("%d issue(s) found", len(report.Issues))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.CatalogPath, "catalog", options.CatalogPath, "Paths to docker catalogs (absolute or relative to ~/.docker/mcp/catalogs/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalCatalogs, "additional-catalog", nil, "Additional catalog paths to append to the default catalogs")
+	cmd.Flags().StringSliceVar(&options.RegistryPath, "registry", options.RegistryPath, "Paths to the registry files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalRegistries, "additional-registry", nil, "Additional registry paths to merge with the default registry.yaml")
+	cmd.Flags().StringSliceVar(&options.ConfigPath, "config", options.ConfigPath, "Paths to the config files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalConfigs, "additional-config", nil, "Additional config paths to merge with the default config.yaml")
+	cmd.Flags().StringSliceVar(&options.ToolsPath, "tools-config", options.ToolsPath, "Paths to the tools files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringVar(&options.SecretsPath, "secrets", options.SecretsPath, "Colon separated paths to search for secrets. Can be `docker-desktop` or a path to a .env file")
+	cmd.Flags().StringVar(&options.SessionName, "session", "", "Include the runtime changes persisted to the session ~/.docker/mcp/{SessionName}/")
+	cmd.Flags().BoolVar(&options.ToolNamePrefix, "tool-name-prefix", false, "Prefix the tool names with the name of their MCP Server when looking for collisions")
+	cmd.Flags().BoolVar(&validate, "validate", false, "Also run the static checks of gateway validate, failing if issues are found")
+	cmd.Flags().StringVar(&format, "format", "list", "Output format (json|list)")
+
+	return cmd
+}
+
+func printConfigReport(out io.Writer, report *gateway.ConfigReport) {
+	for _, server := range report.Servers {
+		state := "disabled"
+		if server.Enabled {
+			state = "enabled"
+		}
+		fmt.Fprintf(out, "%s (%s)\n", server.Name, state)
+
+		switch {
+		case server.Image != "":
+			fmt.Fprintf(out, "  image: %s\n", server.Image)
+		case server.RemoteURL != "":
+			fmt.Fprintf(out, "  remote: %s\n", server.RemoteURL)
+		}
+		if len(server.Config) > 0 {
+			fmt.Fprintln(out, "  config:")
+			for _, key := range slices.Sorted(maps.Keys(server.Config)) {
+				fmt.Fprintf(out, "    %s: %v\n", key, server.Config[key])
+			}
+		}
+		if len(server.Secrets) > 0 {
+			fmt.Fprintln(out, "  secrets:")
+			for _, secret := range server.Secrets {
+				status := "missing"
+				if secret.Set {
+					status = "set"
+				}
+				fmt.Fprintf(out, "    %s: %s\n", secret.Name, status)
+			}
+		}
+		if len(server.Tools) > 0 {
+			fmt.Fprintf(out, "  tools: %s\n", strings.Join(server.Tools, ", "))
+		}
+	}
+
+	if len(report.Issues) > 0 {
+		fmt.Fprintln(out, "Issues:")
+		for _, issue := range report.Issues {
+			fmt.Fprintf(out, "  %s\n", issue.String())
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package commands
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
 	"github.com/docker/cli/cli/command"
@@ -11,6 +13,7 @@ import (
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
 	catalogTypes "github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/gateway"
 )
@@ -77,8 +80,8 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 			// Check if dynamic tools feature is enabled
 			options.DynamicTools = isDynamicToolsFeatureEnabled(dockerCli)
 
-			// Check if tool name prefix feature is enabled
-			options.ToolNamePrefix = isToolNamePrefixFeatureEnabled(dockerCli)
+			// Check if tool name prefix feature is enabled, unless --tool-name-prefix is set
+			options.ToolNamePrefix = options.ToolNamePrefix || isToolNamePrefixFeatureEnabled(dockerCli)
 
 			// Update catalog URL based on mcp-oauth-dcr flag if using default Docker catalog URL
 			if len(options.CatalogPath) == 1 && (options.CatalogPath[0] == catalog.DockerCatalogURLV2 || options.CatalogPath[0] == catalog.DockerCatalogURLV3) {
@@ -112,6 +115,10 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 			options.ConfigPath = append(options.ConfigPath, additionalConfigs...)
 			options.ToolsPath = append(options.ToolsPath, additionalToolsConfig...)
 
+			if options.Transport == "stdio" && slices.Contains(slices.Concat(options.CatalogPath, options.RegistryPath, options.ConfigPath, options.ToolsPath), config.StdinPath) {
+				return errors.New("cannot read configuration from stdin with --transport=stdio")
+			}
+
 			// Process MCP registry URLs if provided
 			if len(mcpRegistryUrls) > 0 {
 				var mcpServers []catalogTypes.Server
@@ -175,16 +182,18 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 		runCmd.Flags().StringVar(&options.WorkingSet, "profile", "", "Profile ID to use (mutually exclusive with --servers and --enable-all-servers)")
 	}
 	runCmd.Flags().BoolVar(&enableAllServers, "enable-all-servers", false, "Enable all servers in the catalog (instead of using individual --servers options)")
-	runCmd.Flags().StringSliceVar(&options.CatalogPath, "catalog", options.CatalogPath, "Paths to docker catalogs (absolute or relative to ~/.docker/mcp/catalogs/)")
+	runCmd.Flags().StringSliceVar(&options.CatalogPath, "catalog", options.CatalogPath, "Paths to docker catalogs (absolute or relative to ~/.docker/mcp/catalogs/, an http(s) URL or '-' for stdin)")
 	runCmd.Flags().StringSliceVar(&additionalCatalogs, "additional-catalog", nil, "Additional catalog paths to append to the default catalogs")
-	runCmd.Flags().StringSliceVar(&options.RegistryPath, "registry", options.RegistryPath, "Paths to the registry files (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().StringSliceVar(&options.RegistryPath, "registry", options.RegistryPath, "Paths to the registry files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
 	runCmd.Flags().StringSliceVar(&additionalRegistries, "additional-registry", nil, "Additional registry paths to merge with the default registry.yaml")
-	runCmd.Flags().StringSliceVar(&options.ConfigPath, "config", options.ConfigPath, "Paths to the config files (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().StringSliceVar(&options.ConfigPath, "config", options.ConfigPath, "Paths to the config files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
 	runCmd.Flags().StringSliceVar(&additionalConfigs, "additional-config", nil, "Additional config paths to merge with the default config.yaml")
-	runCmd.Flags().StringSliceVar(&options.ToolsPath, "tools-config", options.ToolsPath, "Paths to the tools files (absolute or relative to ~/.docker/mcp/)")
+	runCmd.Flags().StringSliceVar(&options.ToolsPath, "tools-config", options.ToolsPath, "Paths to the tools files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
 	runCmd.Flags().StringSliceVar(&additionalToolsConfig, "additional-tools-config", nil, "Additional tools paths to merge with the default tools.yaml")
 	runCmd.Flags().StringVar(&options.SecretsPath, "secrets", options.SecretsPath, "Colon separated paths to search for secrets. Can be `docker-desktop` or a path to a .env file (default to using Docker Desktop's secrets API)")
 	runCmd.Flags().StringSliceVar(&options.ToolNames, "tools", options.ToolNames, "List of tools to enable")
+	runCmd.Flags().BoolVar(&options.DiscoverableTools, "discoverable-tools", options.DiscoverableTools, "Only list the always-on tools to the client, the other tools are discovered with list-tools and called with mcp-exec (requires the dynamic tools)")
+	runCmd.Flags().StringSliceVar(&options.AlwaysOnTools, "always-on-tools", options.AlwaysOnTools, "List of tools always listed to the client with --discoverable-tools, in the same formats as --tools (in addition to the registry's alwaysOnTools)")
 	runCmd.Flags().StringArrayVar(&options.Interceptors, "interceptor", options.Interceptors, "List of interceptors to use (format: when:type:path, e.g. 'before:exec:/bin/path')")
 	runCmd.Flags().StringArrayVar(&options.OciRef, "oci-ref", options.OciRef, "OCI image references to use")
 	runCmd.Flags().StringSliceVar(&mcpRegistryUrls, "mcp-registry", nil, "MCP registry URLs to fetch servers from (can be repeated)")
@@ -196,11 +205,34 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	runCmd.Flags().BoolVar(&options.VerifySignatures, "verify-signatures", options.VerifySignatures, "Verify signatures of the server images")
 	runCmd.Flags().BoolVar(&options.DryRun, "dry-run", options.DryRun, "Start the gateway but do not listen for connections (useful for testing the configuration)")
 	runCmd.Flags().BoolVar(&options.Verbose, "verbose", options.Verbose, "Verbose output")
+	runCmd.Flags().StringVar(&options.LogLevel, "log-level", options.LogLevel, "Minimum level of the logs: debug, info, warn or error (default is info, debug with --verbose)")
 	runCmd.Flags().BoolVar(&options.LongLived, "long-lived", options.LongLived, "Containers are long-lived and will not be removed until the gateway is stopped, useful for stateful servers")
 	runCmd.Flags().BoolVar(&options.DebugDNS, "debug-dns", options.DebugDNS, "Debug DNS resolution")
 	runCmd.Flags().BoolVar(&options.Watch, "watch", options.Watch, "Watch for changes and reconfigure the gateway")
+	runCmd.Flags().StringSliceVar(&options.ToolQuotas, "tool-quota", nil, "Limit the calls to a tool per window of time, as tool=limit/window (e.g. search=100/24h). Counters are persisted in ~/.docker/mcp/quotas.json")
+	runCmd.Flags().DurationVar(&options.FindCacheTTL, "find-cache-ttl", 0, "Cache the mcp-find results for this long, until the configuration reloads (0 to disable)")
+	runCmd.Flags().DurationVar(&options.ToolCacheTTL, "tool-cache-ttl", 0, "Cache the results of the tools listed in the registry's cacheableTools for this long, keyed on their arguments (0 to disable)")
+	runCmd.Flags().StringVar(&options.FindRankField, "find-rank-field", options.FindRankField, "Catalog metadata blended with the relevance to rank the mcp-find results: pulls, stars or githubStars (default is relevance only)")
+	runCmd.Flags().Float64Var(&options.FindRankWeight, "find-rank-weight", options.FindRankWeight, "Weight, between 0 and 1, of --find-rank-field against the relevance when ranking the mcp-find results")
+	runCmd.Flags().Float64Var(&options.FindFeedbackWeight, "find-feedback-weight", options.FindFeedbackWeight, "Weight, between 0 and 1, of the servers whose tools were reported useful for a query with record-tool-feedback when ranking the mcp-find results. Feedback is persisted in ~/.docker/mcp/tool-feedback.json (default is no feedback)")
+	runCmd.Flags().StringVar(&options.FindLogPath, "find-log", options.FindLogPath, "Append each mcp-find query, with secrets redacted, and the returned servers and scores to this JSONL file (default is no logging)")
+	runCmd.Flags().DurationVar(&options.PollInterval, "poll-interval", 0, "When watching, poll http(s) catalog and configuration URLs for changes at this interval (0 to disable)")
 	runCmd.Flags().IntVar(&options.Cpus, "cpus", options.Cpus, "CPUs allocated to each MCP Server (default is 1)")
 	runCmd.Flags().StringVar(&options.Memory, "memory", options.Memory, "Memory allocated to each MCP Server (default is 2Gb)")
+	runCmd.Flags().IntVar(&options.MaxConcurrentLaunches, "max-concurrent-launches", options.MaxConcurrentLaunches, "Maximum number of images pulled or MCP Servers started at the same time (default is GOMAXPROCS)")
+	runCmd.Flags().BoolVar(&options.FailFastPull, "fail-fast-pull", options.FailFastPull, "Stop at the first image that can't be pulled (default is to report all of them)")
+	runCmd.Flags().DurationVar(&options.PullTimeout, "pull-timeout", options.PullTimeout, "Maximum duration of the pull of all the images, cancelling the outstanding pulls when it's reached (default is no timeout)")
+	runCmd.Flags().BoolVar(&options.ReadOnlyConfig, "read-only-config", options.ReadOnlyConfig, "Prevent the dynamic tools from changing the configuration (mcp-config-set and probe-server fail)")
+	runCmd.Flags().DurationVar(&options.ToolCallTimeout, "tool-timeout", options.ToolCallTimeout, "Maximum duration of a call to an MCP Server's tool (default is no timeout)")
+	runCmd.Flags().StringVar(&options.JSONFormat, "json-format", options.JSONFormat, "Format of the JSON returned by the gateway's own tools: indented or compact (default is indented for diagnostics like stats, compact for search results)")
+	runCmd.Flags().BoolVar(&options.StructuredContent, "structured-content", options.StructuredContent, "Also return the JSON responses of the gateway's own tools as structured content, for the clients that support it")
+	runCmd.Flags().StringVar(&options.InternalToolsDir, "internal-tools-dir", options.InternalToolsDir, "Directory under which the gateway's own tools persist their files (sessions, secrets set with mcp-config-set, quota counters), rejecting paths outside of it (default is ~/.docker/mcp/)")
+	runCmd.Flags().DurationVar(&options.InternalToolTimeout, "internal-tool-timeout", options.InternalToolTimeout, "Maximum duration of a call to one of the gateway's own tools, e.g. mcp-find (default is no timeout)")
+	runCmd.Flags().BoolVar(&options.ToolNamePrefix, "tool-name-prefix", options.ToolNamePrefix, "Prefix the tool names with the name of their MCP Server (e.g. github:get_me), to avoid collisions")
+	runCmd.Flags().BoolVar(&options.AllowPrivilegedRunArgs, "allow-privileged-run-args", options.AllowPrivilegedRunArgs, "Allow the registry's extraRunArgs to use flags that weaken the containers' isolation (e.g. --privileged)")
+	runCmd.Flags().StringVar(&options.RunPolicyPath, "run-policy", options.RunPolicyPath, "Yaml file of the docker run flags, capabilities and networks that the registry's server entries can use (default allows only the flags known not to weaken the containers' isolation, and denies the host network)")
+	runCmd.Flags().StringSliceVar(&options.AllowedEnvReferences, "allow-env-reference", options.AllowedEnvReferences, "Environment variables of the gateway that the servers' config can reference with ${env:NAME} (can be repeated)")
+	runCmd.Flags().StringSliceVar(&options.AllowedMountRoots, "allowed-mount-root", options.AllowedMountRoots, "Host directories under which the registry's volumes can be mounted into the containers (can be repeated)")
 	runCmd.Flags().BoolVar(&options.Static, "static", options.Static, "Enable static mode (aka pre-started servers)")
 	runCmd.Flags().StringVar(&options.LogFilePath, "log", options.LogFilePath, "Path to log file for stderr output (relative or absolute)")
 	runCmd.Flags().StringVar(&options.SessionName, "session", "", "Session name for loading and persisting configuration from ~/.docker/mcp/{SessionName}/")
@@ -209,10 +241,95 @@ func gatewayCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command
 	_ = runCmd.Flags().MarkHidden("log")
 
 	cmd.AddCommand(runCmd)
+	cmd.AddCommand(gatewayValidateCommand(docker, dockerCli, options))
 
 	return cmd
 }
 
+func gatewayValidateCommand(docker docker.Client, dockerCli command.Cli, defaults gateway.Config) *cobra.Command {
+	// Only the paths to the catalogs and the configuration matter, nothing is started.
+	options := gateway.Config{
+		CatalogPath:  defaults.CatalogPath,
+		RegistryPath: defaults.RegistryPath,
+		ConfigPath:   defaults.ConfigPath,
+		ToolsPath:    defaults.ToolsPath,
+	}
+	var additionalCatalogs []string
+	var additionalRegistries []string
+	var additionalConfigs []string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the catalogs, the registry and the config without pulling images or starting servers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "list" && format != "json" {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+
+			options.ToolNamePrefix = options.ToolNamePrefix || isToolNamePrefixFeatureEnabled(dockerCli)
+			applyConfigurationPaths(dockerCli, &options, additionalCatalogs, additionalRegistries, additionalConfigs)
+
+			issues := gateway.NewGateway(options, docker).Validate(cmd.Context())
+
+			if format == "json" {
+				if issues == nil {
+					issues = []gateway.ValidationIssue{}
+				}
+				buf, err := json.MarshalIndent(issues, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+			} else {
+				for _, issue := range issues {
+					fmt.Fprintln(cmd.OutOrStdout(), issue.String())
+				}
+			}
+
+			if len(issues) > 0 {
+				return fmt.Errorf("%d issue(s) found", len(issues))
+			}
+			if format == "list" {
+				fmt.Fprintln(cmd.OutOrStdout(), "No issues found")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.ServerNames, "servers", nil, "Names of the servers to check as enabled (if non empty, ignore --registry flag)")
+	cmd.Flags().StringSliceVar(&options.CatalogPath, "catalog", options.CatalogPath, "Paths to docker catalogs (absolute or relative to ~/.docker/mcp/catalogs/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalCatalogs, "additional-catalog", nil, "Additional catalog paths to append to the default catalogs")
+	cmd.Flags().StringSliceVar(&options.RegistryPath, "registry", options.RegistryPath, "Paths to the registry files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalRegistries, "additional-registry", nil, "Additional registry paths to merge with the default registry.yaml")
+	cmd.Flags().StringSliceVar(&options.ConfigPath, "config", options.ConfigPath, "Paths to the config files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalConfigs, "additional-config", nil, "Additional config paths to merge with the default config.yaml")
+	cmd.Flags().BoolVar(&options.ToolNamePrefix, "tool-name-prefix", false, "Prefix the tool names with the name of their MCP Server when looking for collisions")
+	cmd.Flags().StringVar(&format, "format", "list", "Output format (json|list)")
+
+	return cmd
+}
+
+// applyConfigurationPaths resolves the catalogs the way gateway run does, and appends the additional
+// catalogs, registries and configs, for the commands that only read the configuration.
+func applyConfigurationPaths(dockerCli command.Cli, options *gateway.Config, additionalCatalogs, additionalRegistries, additionalConfigs []string) {
+	options.McpOAuthDcrEnabled = isMcpOAuthDcrFeatureEnabled(dockerCli)
+
+	if len(options.CatalogPath) == 1 && (options.CatalogPath[0] == catalog.DockerCatalogURLV2 || options.CatalogPath[0] == catalog.DockerCatalogURLV3) {
+		options.CatalogPath[0] = catalog.GetDockerCatalogURL(options.McpOAuthDcrEnabled)
+	}
+
+	defaultPaths := convertCatalogNamesToPaths(options.CatalogPath)
+	var configuredPaths []string
+	if len(defaultPaths) == 1 && (defaultPaths[0] == catalog.DockerCatalogURLV2 || defaultPaths[0] == catalog.DockerCatalogURLV3 || defaultPaths[0] == catalog.DockerCatalogFilename) {
+		configuredPaths = getConfiguredCatalogPaths()
+	}
+	options.CatalogPath = buildUniqueCatalogPaths(defaultPaths, configuredPaths, additionalCatalogs)
+	options.RegistryPath = append(options.RegistryPath, additionalRegistries...)
+	options.ConfigPath = append(options.ConfigPath, additionalConfigs...)
+}
+
 // getConfiguredCatalogPaths returns the file paths of all configured catalogs
 func getConfiguredCatalogPaths() []string {
 	cfg, err := catalog.ReadConfig()
@@ -90,13 +90,13 @@ func Root(ctx context.Context, cwd string, dockerCli command.Cli) *cobra.Command
 	}
 	cmd.AddCommand(catalogCommand(dockerCli))
 	cmd.AddCommand(clientCommand(dockerCli, cwd))
-	cmd.AddCommand(configCommand(dockerClient))
+	cmd.AddCommand(configCommand(dockerClient, dockerCli))
 	cmd.AddCommand(featureCommand(dockerCli))
 	cmd.AddCommand(gatewayCommand(dockerClient, dockerCli))
 	cmd.AddCommand(oauthCommand())
 	cmd.AddCommand(policyCommand())
 	cmd.AddCommand(registryCommand())
-	cmd.AddCommand(secretCommand(dockerClient))
+	cmd.AddCommand(secretCommand(dockerClient, dockerCli))
 	cmd.AddCommand(serverCommand(dockerClient, dockerCli))
 	cmd.AddCommand(toolsCommand(dockerClient, dockerCli))
 	cmd.AddCommand(versionCommand())
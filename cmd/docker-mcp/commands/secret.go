@@ -1,14 +1,21 @@
 package commands
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/docker/cli/cli/command"
 	"github.com/spf13/cobra"
 
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/catalog"
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/formatting"
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/secret"
 	"github.com/docker/mcp-gateway/pkg/docker"
+	"github.com/docker/mcp-gateway/pkg/gateway"
 )
 
 const setSecretExample = `
@@ -21,9 +28,13 @@ const setSecretExample = `
 
 > echo my-secret-password > pwd.txt
 > cat pwd.txt | docker mcp secret set POSTGRES_PASSWORD
+
+### Type the secret without echoing it, from a terminal
+
+> docker mcp secret set POSTGRES_PASSWORD
 `
 
-func secretCommand(docker docker.Client) *cobra.Command {
+func secretCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "secret",
 		Short:   "Manage secrets",
@@ -33,6 +44,7 @@ func secretCommand(docker docker.Client) *cobra.Command {
 	cmd.AddCommand(listSecretCommand())
 	cmd.AddCommand(setSecretCommand())
 	cmd.AddCommand(exportSecretCommand(docker))
+	cmd.AddCommand(missingSecretCommand(docker, dockerCli))
 	return cmd
 }
 
@@ -61,7 +73,7 @@ func validateRmArgs(args []string, opts secret.RmOpts) error {
 }
 
 func listSecretCommand() *cobra.Command {
-	var opts secret.ListOptions
+	opts := secret.ListOptions{Format: secret.Table}
 	cmd := &cobra.Command{
 		Use:   "ls",
 		Short: "List all secret names in the secrets file",
@@ -72,6 +84,8 @@ func listSecretCommand() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	flags.BoolVar(&opts.JSON, "json", false, "Print as JSON.")
+	flags.Var(&opts.Format, "format", fmt.Sprintf("Output format. Supported: %s.", secret.SupportedFormats()))
+	flags.DurationVar(&opts.Since, "since", 0, "Only list the secrets modified within this duration (e.g. 24h).")
 	return cmd
 }
 
@@ -132,3 +146,67 @@ func exportSecretCommand(docker docker.Client) *cobra.Command {
 		},
 	}
 }
+
+func missingSecretCommand(docker docker.Client, dockerCli command.Cli) *cobra.Command {
+	// Same files as the on-host gateway. Nothing is started.
+	options := gateway.Config{
+		CatalogPath:  []string{catalog.DockerCatalogFilename},
+		RegistryPath: []string{"registry.yaml"},
+		ConfigPath:   []string{"config.yaml"},
+		ToolsPath:    []string{"tools.yaml"},
+		SecretsPath:  "secrets.env:docker-desktop",
+	}
+	var additionalCatalogs []string
+	var additionalRegistries []string
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "missing",
+		Short: "List the secrets required by the enabled servers that are not set",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("unsupported format: %s", format)
+			}
+
+			applyConfigurationPaths(dockerCli, &options, additionalCatalogs, additionalRegistries, nil)
+
+			missing, err := gateway.NewGateway(options, docker).MissingSecrets(cmd.Context())
+			if err != nil {
+				return err
+			}
+
+			if format == "json" {
+				buf, err := json.MarshalIndent(missing, "", "  ")
+				if err != nil {
+					return err
+				}
+				fmt.Fprintln(cmd.OutOrStdout(), string(buf))
+			} else {
+				var rows [][]string
+				for _, serverName := range slices.Sorted(maps.Keys(missing)) {
+					rows = append(rows, []string{serverName, strings.Join(missing[serverName], ", ")})
+				}
+				formatting.PrettyPrintTable(rows, []int{40, 120}, []string{"SERVER", "MISSING SECRETS"})
+			}
+
+			count := 0
+			for _, secrets := range missing {
+				count += len(secrets)
+			}
+			if count > 0 {
+				return fmt.Errorf("%d secret(s) missing", count)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.CatalogPath, "catalog", options.CatalogPath, "Paths to docker catalogs (absolute or relative to ~/.docker/mcp/catalogs/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalCatalogs, "additional-catalog", nil, "Additional catalog paths to append to the default catalogs")
+	cmd.Flags().StringSliceVar(&options.RegistryPath, "registry", options.RegistryPath, "Paths to the registry files (absolute or relative to ~/.docker/mcp/, an http(s) URL or '-' for stdin)")
+	cmd.Flags().StringSliceVar(&additionalRegistries, "additional-registry", nil, "Additional registry paths to merge with the default registry.yaml")
+	cmd.Flags().StringVar(&options.SecretsPath, "secrets", options.SecretsPath, "Colon separated paths to search for secrets. Can be `docker-desktop` or a path to a .env file")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format (json|table)")
+
+	return cmd
+}
@@ -9,15 +9,33 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/log"
 )
 
 const DefaultSecretsFile = "secrets.env"
 
+// CaseInsensitiveEnv, when set to 1, makes the secret names case-insensitive.
+const CaseInsensitiveEnv = config.SecretsCaseInsensitiveEnv
+
+// modifiedPrefix is the comment written above each secret to record when it was last modified.
+// Being a comment, it's ignored by the readers that only care about the values.
+const modifiedPrefix = "# modified: "
+
+// fileSecret is a secret value and the time it was last modified (zero if unknown)
+type fileSecret struct {
+	value    string
+	modified time.Time
+}
+
 // FileSecrets represents a file-based secrets store
 type FileSecrets struct {
 	Path string
+	// CaseInsensitive makes `Token` and `TOKEN` the same secret. The secrets that are set are
+	// stored with a lowercase name, the others keep the name they have in the file.
+	CaseInsensitive bool
 }
 
 // NewFileSecrets creates a new FileSecrets instance
@@ -27,7 +45,38 @@ func NewFileSecrets() (*FileSecrets, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &FileSecrets{Path: path}, nil
+	return &FileSecrets{
+		Path:            path,
+		CaseInsensitive: os.Getenv(CaseInsensitiveEnv) == "1",
+	}, nil
+}
+
+// canonicalName returns the name under which a secret is written
+func (f *FileSecrets) canonicalName(name string) string {
+	if f.CaseInsensitive {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+// sameName tells whether two names are the name of the same secret
+func (f *FileSecrets) sameName(a, b string) bool {
+	if f.CaseInsensitive {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// deleteName removes the secrets with the given name and returns how many were removed
+func (f *FileSecrets) deleteName(secrets map[string]fileSecret, name string) int {
+	removed := 0
+	for key := range secrets {
+		if f.sameName(key, name) {
+			delete(secrets, key)
+			removed++
+		}
+	}
+	return removed
 }
 
 // List returns all secret names from the file
@@ -41,11 +90,15 @@ func (f *FileSecrets) List(ctx context.Context) ([]StoredSecret, error) {
 	}
 
 	var result []StoredSecret
-	for name := range secrets {
-		result = append(result, StoredSecret{
+	for name, secret := range secrets {
+		storedSecret := StoredSecret{
 			Name:     name,
 			Provider: "file",
-		})
+		}
+		if !secret.modified.IsZero() {
+			storedSecret.Modified = &secret.modified
+		}
+		result = append(result, storedSecret)
 	}
 
 	// Sort by name for consistent output
@@ -61,13 +114,17 @@ func (f *FileSecrets) Set(ctx context.Context, name, value string) error {
 	secrets, err := f.readAll(ctx)
 	if err != nil {
 		if os.IsNotExist(err) {
-			secrets = make(map[string]string)
+			secrets = make(map[string]fileSecret)
 		} else {
 			return err
 		}
 	}
 
-	secrets[name] = value
+	f.deleteName(secrets, name)
+	secrets[f.canonicalName(name)] = fileSecret{
+		value:    value,
+		modified: time.Now().UTC().Truncate(time.Second),
+	}
 	return f.writeAll(secrets)
 }
 
@@ -81,28 +138,29 @@ func (f *FileSecrets) Delete(ctx context.Context, name string) error {
 		return err
 	}
 
-	if _, ok := secrets[name]; !ok {
+	if f.deleteName(secrets, name) == 0 {
 		return fmt.Errorf("secret %s not found", name)
 	}
 
-	delete(secrets, name)
 	return f.writeAll(secrets)
 }
 
 // DeleteAll removes all secrets from the file
 func (f *FileSecrets) DeleteAll(ctx context.Context) error {
-	return f.writeAll(make(map[string]string))
+	return f.writeAll(make(map[string]fileSecret))
 }
 
-// readAll reads all secrets from the file
-func (f *FileSecrets) readAll(ctx context.Context) (map[string]string, error) {
-	secrets := make(map[string]string)
+// readAll reads all secrets from the file, with the names they have in the file.
+// When a secret is defined more than once, the last value wins.
+func (f *FileSecrets) readAll(ctx context.Context) (map[string]fileSecret, error) {
+	secrets := make(map[string]fileSecret)
 
 	buf, err := os.ReadFile(f.Path)
 	if err != nil {
 		return nil, err
 	}
 
+	var modified time.Time
 	scanner := bufio.NewScanner(bytes.NewReader(buf))
 	for scanner.Scan() {
 		if ctx.Err() != nil {
@@ -110,6 +168,11 @@ func (f *FileSecrets) readAll(ctx context.Context) (map[string]string, error) {
 		}
 
 		line := scanner.Text()
+		if timestamp, ok := strings.CutPrefix(line, modifiedPrefix); ok {
+			// Applies to the secret on the next line. Invalid timestamps mean unknown.
+			modified, _ = time.Parse(time.RFC3339, strings.TrimSpace(timestamp))
+			continue
+		}
 		if strings.HasPrefix(line, "#") {
 			continue
 		}
@@ -124,14 +187,22 @@ func (f *FileSecrets) readAll(ctx context.Context) (map[string]string, error) {
 			continue // Skip invalid lines
 		}
 
-		secrets[key] = value
+		if f.deleteName(secrets, key) > 0 {
+			log.Warn(fmt.Sprintf("secret %s is defined more than once in %s, using the last value", key, f.Path))
+		}
+
+		secrets[key] = fileSecret{
+			value:    value,
+			modified: modified,
+		}
+		modified = time.Time{}
 	}
 
 	return secrets, scanner.Err()
 }
 
 // writeAll writes all secrets to the file
-func (f *FileSecrets) writeAll(secrets map[string]string) error {
+func (f *FileSecrets) writeAll(secrets map[string]fileSecret) error {
 	// Ensure directory exists
 	dir := filepath.Dir(f.Path)
 	if err := os.MkdirAll(dir, 0o755); err != nil {
@@ -147,7 +218,10 @@ func (f *FileSecrets) writeAll(secrets map[string]string) error {
 
 	var buf bytes.Buffer
 	for _, k := range keys {
-		buf.WriteString(fmt.Sprintf("%s=%s\n", k, secrets[k]))
+		if modified := secrets[k].modified; !modified.IsZero() {
+			buf.WriteString(modifiedPrefix + modified.Format(time.RFC3339) + "\n")
+		}
+		buf.WriteString(fmt.Sprintf("%s=%s\n", k, secrets[k].value))
 	}
 
 	return os.WriteFile(f.Path, buf.Bytes(), 0o600)
@@ -155,6 +229,7 @@ func (f *FileSecrets) writeAll(secrets map[string]string) error {
 
 // StoredSecret represents a secret stored in the file (matches desktop.StoredSecret interface)
 type StoredSecret struct {
-	Name     string `json:"name"`
-	Provider string `json:"provider,omitempty"`
+	Name     string     `json:"name"`
+	Provider string     `json:"provider,omitempty"`
+	Modified *time.Time `json:"modified,omitempty"`
 }
@@ -2,14 +2,60 @@ package secret
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/docker/mcp-gateway/cmd/docker-mcp/secret-management/formatting"
 )
 
+type Format string
+
+const (
+	Table Format = "table"
+	JSON  Format = "json"
+	CSV   Format = "csv"
+	Names Format = "names"
+)
+
+var supportedFormats = []Format{Table, JSON, CSV, Names}
+
+func (e *Format) String() string {
+	return string(*e)
+}
+
+func (e *Format) Set(v string) error {
+	actual := Format(v)
+	for _, allowed := range supportedFormats {
+		if allowed == actual {
+			*e = actual
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of %s", SupportedFormats())
+}
+
+// Type is only used in help text
+func (e *Format) Type() string {
+	return "format"
+}
+
+func SupportedFormats() string {
+	var quoted []string
+	for _, v := range supportedFormats {
+		quoted = append(quoted, "\""+string(v)+"\"")
+	}
+	return strings.Join(quoted, ", ")
+}
+
 type ListOptions struct {
-	JSON bool
+	JSON   bool
+	Format Format
+	Since  time.Duration
 }
 
 func List(ctx context.Context, opts ListOptions) error {
@@ -23,7 +69,17 @@ func List(ctx context.Context, opts ListOptions) error {
 		return err
 	}
 
+	if opts.Since > 0 {
+		l = modifiedSince(l, time.Now().Add(-opts.Since))
+	}
+
+	format := opts.Format
 	if opts.JSON {
+		format = JSON
+	}
+
+	switch format {
+	case JSON:
 		if len(l) == 0 {
 			l = []StoredSecret{} // Guarantee empty list (instead of displaying null)
 		}
@@ -33,11 +89,64 @@ func List(ctx context.Context, opts ListOptions) error {
 		}
 		fmt.Println(string(jsonData))
 		return nil
+	case CSV:
+		return writeCSV(os.Stdout, l)
+	case Names:
+		return writeNames(os.Stdout, l)
 	}
+
 	var rows [][]string
 	for _, v := range l {
-		rows = append(rows, []string{v.Name, v.Provider})
+		rows = append(rows, []string{v.Name, v.Provider, formatModified(v.Modified)})
 	}
-	formatting.PrettyPrintTable(rows, []int{40, 120})
+	formatting.PrettyPrintTable(rows, []int{40, 120, 20})
 	return nil
 }
+
+// writeCSV writes the secrets as CSV, with a header row.
+func writeCSV(w io.Writer, secrets []StoredSecret) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{"name", "provider", "modified"}); err != nil {
+		return err
+	}
+	for _, secret := range secrets {
+		modified := ""
+		if secret.Modified != nil {
+			modified = secret.Modified.Format(time.RFC3339)
+		}
+		if err := csvWriter.Write([]string{secret.Name, secret.Provider, modified}); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	return csvWriter.Error()
+}
+
+// writeNames writes the secret names, one per line.
+func writeNames(w io.Writer, secrets []StoredSecret) error {
+	for _, secret := range secrets {
+		if _, err := fmt.Fprintln(w, secret.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatModified(modified *time.Time) string {
+	if modified == nil {
+		return "unknown"
+	}
+	return modified.Local().Format(time.DateTime)
+}
+
+// modifiedSince keeps the secrets modified after the given time.
+// Secrets with an unknown modification time are filtered out.
+func modifiedSince(secrets []StoredSecret, since time.Time) []StoredSecret {
+	var result []StoredSecret
+	for _, secret := range secrets {
+		if secret.Modified != nil && !secret.Modified.Before(since) {
+			result = append(result, secret)
+		}
+	}
+	return result
+}
@@ -1,8 +1,12 @@
 package secret
 
 import (
+	"bytes"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,6 +35,13 @@ func TestParseArg(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestTrimNewline(t *testing.T) {
+	assert.Equal(t, "my-secret-password", trimNewline("my-secret-password\n"))
+	assert.Equal(t, "my-secret-password", trimNewline("my-secret-password\r\n"))
+	assert.Equal(t, "my-secret-password", trimNewline("my-secret-password"))
+	assert.Equal(t, "value\n", trimNewline("value\n\n"))
+}
+
 func TestIsDirectValueProvider(t *testing.T) {
 	assert.True(t, isDirectValueProvider(""))
 	assert.True(t, isDirectValueProvider(Credstore))
@@ -61,3 +72,108 @@ func TestIsErrDecryption(t *testing.T) {
 	// Test nil
 	assert.False(t, isErrDecryption(nil))
 }
+
+func TestFileSecretsModified(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	require.NoError(t, os.WriteFile(path, []byte("legacy=value\n"), 0o600))
+
+	fs := &FileSecrets{Path: path}
+	require.NoError(t, fs.Set(t.Context(), "github.token", "ghp_123"))
+
+	secrets, err := fs.List(t.Context())
+	require.NoError(t, err)
+	require.Len(t, secrets, 2)
+
+	assert.Equal(t, "github.token", secrets[0].Name)
+	require.NotNil(t, secrets[0].Modified)
+	assert.WithinDuration(t, time.Now(), *secrets[0].Modified, time.Minute)
+
+	assert.Equal(t, "legacy", secrets[1].Name)
+	assert.Nil(t, secrets[1].Modified)
+
+	// Values are unchanged for readers that ignore comments
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf), "github.token=ghp_123\n")
+	assert.Contains(t, string(buf), "legacy=value\n")
+}
+
+func TestFileSecretsDuplicates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	require.NoError(t, os.WriteFile(path, []byte("token=first\nToken=other\ntoken=last\n"), 0o600))
+
+	fs := &FileSecrets{Path: path}
+	secrets, err := fs.readAll(t.Context())
+	require.NoError(t, err)
+
+	assert.Len(t, secrets, 2)
+	assert.Equal(t, "last", secrets["token"].value)
+	assert.Equal(t, "other", secrets["Token"].value)
+}
+
+func TestFileSecretsCaseInsensitive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	require.NoError(t, os.WriteFile(path, []byte("TOKEN=old\nGITHUB_TOKEN=ghp_123\n"), 0o600))
+
+	fs := &FileSecrets{Path: path, CaseInsensitive: true}
+	require.NoError(t, fs.Set(t.Context(), "Token", "new"))
+
+	secrets, err := fs.readAll(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, secrets, 2)
+	assert.Equal(t, "new", secrets["token"].value)
+	// The other secrets keep their name
+	assert.Equal(t, "ghp_123", secrets["GITHUB_TOKEN"].value)
+
+	require.NoError(t, fs.Delete(t.Context(), "TOKEN"))
+	require.Error(t, fs.Delete(t.Context(), "TOKEN"))
+
+	secrets, err = fs.readAll(t.Context())
+	require.NoError(t, err)
+	assert.Len(t, secrets, 1)
+	assert.Contains(t, secrets, "GITHUB_TOKEN")
+}
+
+func TestModifiedSince(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	secrets := []StoredSecret{
+		{Name: "old", Modified: &old},
+		{Name: "recent", Modified: &recent},
+		{Name: "unknown"},
+	}
+
+	result := modifiedSince(secrets, now.Add(-24*time.Hour))
+	require.Len(t, result, 1)
+	assert.Equal(t, "recent", result[0].Name)
+}
+
+func TestWriteCSV(t *testing.T) {
+	modified := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	secrets := []StoredSecret{
+		{Name: "github.token", Provider: "docker-desktop", Modified: &modified},
+		{Name: "api,key", Provider: "file"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeCSV(&buf, secrets))
+
+	assert.Equal(t, "name,provider,modified\ngithub.token,docker-desktop,2025-01-02T03:04:05Z\n\"api,key\",file,\n", buf.String())
+}
+
+func TestWriteNames(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeNames(&buf, []StoredSecret{{Name: "first"}, {Name: "second"}}))
+
+	assert.Equal(t, "first\nsecond\n", buf.String())
+}
+
+func TestFormatSet(t *testing.T) {
+	var format Format
+	require.NoError(t, format.Set("csv"))
+	assert.Equal(t, CSV, format)
+
+	require.Error(t, format.Set("xml"))
+}
@@ -17,7 +17,21 @@ type SetOpts struct {
 	Provider string
 }
 
+// MappingFromSTDIN reads the value of a secret from stdin, so that it never appears
+// in the shell history. On a terminal, the user is prompted and the value isn't echoed.
 func MappingFromSTDIN(ctx context.Context, key string) (*Secret, error) {
+	if tui.IsTerminal(os.Stdin) {
+		value, err := tui.ReadPassword(fmt.Sprintf("Enter the value of secret %s: ", key))
+		if err != nil {
+			return nil, err
+		}
+
+		return &Secret{
+			key: key,
+			val: value,
+		}, nil
+	}
+
 	data, err := tui.ReadAllWithContext(ctx, os.Stdin)
 	if err != nil {
 		return nil, err
@@ -25,10 +39,16 @@ func MappingFromSTDIN(ctx context.Context, key string) (*Secret, error) {
 
 	return &Secret{
 		key: key,
-		val: string(data),
+		val: trimNewline(string(data)),
 	}, nil
 }
 
+// trimNewline removes the newline that ends a value piped with `echo` or read from a file.
+func trimNewline(value string) string {
+	value = strings.TrimSuffix(value, "\n")
+	return strings.TrimSuffix(value, "\r")
+}
+
 type Secret struct {
 	key string
 	val string
@@ -25,53 +25,79 @@ func Get(ctx context.Context) (Catalog, error) {
 }
 
 func ReadFrom(ctx context.Context, fileOrURLs []string) (Catalog, error) {
-	mergedServers := map[string]Server{}
+	merged := Catalog{
+		Servers: map[string]Server{},
+	}
 
 	for _, fileOrURL := range fileOrURLs {
-		servers, _, _, err := readMCPServers(ctx, fileOrURL)
+		topLevel, err := readMCPServers(ctx, fileOrURL)
 		if err != nil {
 			return Catalog{}, err
 		}
 
-		// Merge servers into the combined map, checking for overlaps
-		for key, server := range servers {
-			if _, exists := mergedServers[key]; exists {
-				log.Printf("Warning: overlapping key '%s' found in catalog '%s', overwriting previous value", key, fileOrURL)
-			}
-			mergedServers[key] = server
+		merged.Merge(Catalog{Servers: topLevel.Registry, Defs: topLevel.Defs}, fileOrURL)
+	}
+
+	return merged, nil
+}
+
+// Merge adds the servers and the definitions of another catalog, overwriting the existing ones.
+func (c *Catalog) Merge(other Catalog, source string) {
+	// Merge servers into the combined map, checking for overlaps
+	for key, server := range other.Servers {
+		if _, exists := c.Servers[key]; exists {
+			log.Printf("Warning: overlapping key '%s' found in catalog '%s', overwriting previous value", key, source)
 		}
+		c.Servers[key] = server
 	}
 
-	return Catalog{
-		Servers: mergedServers,
-	}, nil
+	for name, def := range other.Defs {
+		if c.Defs == nil {
+			c.Defs = map[string]any{}
+		}
+		c.Defs[name] = def
+	}
 }
 
 func ReadOne(ctx context.Context, fileOrURL string) (Catalog, string, string, error) {
-	servers, name, displayName, err := readMCPServers(ctx, fileOrURL)
+	topLevel, err := readMCPServers(ctx, fileOrURL)
 	if err != nil {
 		return Catalog{}, "", "", err
 	}
 	return Catalog{
-		Servers: servers,
-	}, name, displayName, nil
+		Servers: topLevel.Registry,
+		Defs:    topLevel.Defs,
+	}, topLevel.Name, topLevel.DisplayName, nil
+}
+
+// Parse parses a catalog from its yaml content.
+func Parse(buf []byte) (Catalog, error) {
+	var topLevel topLevel
+	if err := yaml.Unmarshal(buf, &topLevel); err != nil {
+		return Catalog{}, err
+	}
+
+	return Catalog{
+		Servers: topLevel.Registry,
+		Defs:    topLevel.Defs,
+	}, nil
 }
 
-func readMCPServers(ctx context.Context, fileOrURL string) (map[string]Server, string, string, error) {
+func readMCPServers(ctx context.Context, fileOrURL string) (topLevel, error) {
 	buf, err := readFileOrURL(ctx, fileOrURL)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return map[string]Server{}, "", "", nil
+			return topLevel{Registry: map[string]Server{}}, nil
 		}
-		return nil, "", "", err
+		return topLevel{}, err
 	}
 
 	var topLevel topLevel
 	if err := yaml.Unmarshal(buf, &topLevel); err != nil {
-		return nil, "", "", err
+		return topLevel, err
 	}
 
-	return topLevel.Registry, topLevel.Name, topLevel.DisplayName, nil
+	return topLevel, nil
 }
 
 func readFileOrURL(ctx context.Context, fileOrURL string) ([]byte, error) {
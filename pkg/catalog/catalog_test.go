@@ -268,3 +268,66 @@ func setupOverlappingCatalogs(t *testing.T, homeDir string) {
 	err = os.WriteFile(filepath.Join(homeDir, "cli-catalog.yaml"), []byte(cliCatalog), 0o644)
 	require.NoError(t, err)
 }
+
+func TestReadFromMergesDefs(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.yaml")
+	second := filepath.Join(dir, "second.yaml")
+	require.NoError(t, os.WriteFile(first, []byte(`
+$defs:
+  port:
+    type: integer
+  host:
+    type: string
+registry:
+  postgres:
+    image: mcp/postgres
+`), 0o644))
+	require.NoError(t, os.WriteFile(second, []byte(`
+$defs:
+  port:
+    type: integer
+    minimum: 1
+registry:
+  redis:
+    image: mcp/redis
+`), 0o644))
+
+	catalog, err := ReadFrom(t.Context(), []string{first, second})
+	require.NoError(t, err)
+
+	assert.Contains(t, catalog.Servers, "postgres")
+	assert.Contains(t, catalog.Servers, "redis")
+	assert.Equal(t, map[string]any{
+		"port": map[string]any{"type": "integer", "minimum": 1},
+		"host": map[string]any{"type": "string"},
+	}, catalog.Defs)
+}
+
+func TestServerConfigItems(t *testing.T) {
+	server := Server{
+		Config: []any{
+			map[string]any{
+				"name": "postgres",
+				"type": "object",
+				"properties": map[string]any{
+					"port": map[string]any{"type": "integer", "default": 5432},
+					"bad":  "not a schema",
+				},
+			},
+			"not an object",
+			map[string]any{"type": "object"},
+		},
+	}
+
+	items := server.ConfigItems()
+	require.Len(t, items, 2)
+
+	assert.Equal(t, "postgres", items[0].Name)
+	assert.Equal(t, map[string]map[string]any{
+		"port": {"type": "integer", "default": 5432},
+	}, items[0].Properties())
+
+	assert.Empty(t, items[1].Name)
+	assert.Empty(t, items[1].Properties())
+}
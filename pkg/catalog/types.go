@@ -2,6 +2,8 @@ package catalog
 
 type Catalog struct {
 	Servers map[string]Server
+	// Defs are the JSON schema definitions shared by the servers' config schemas
+	Defs map[string]any
 }
 
 // catalog.json
@@ -10,6 +12,7 @@ type topLevel struct {
 	Name        string            `yaml:"name,omitempty" json:"name,omitempty"`
 	DisplayName string            `yaml:"displayName,omitempty" json:"displayName,omitempty"`
 	Registry    map[string]Server `json:"registry"`
+	Defs        map[string]any    `yaml:"$defs,omitempty" json:"$defs,omitempty"`
 }
 
 // MCP Servers
@@ -36,6 +39,10 @@ type Server struct {
 	Config         []any     `yaml:"config,omitempty" json:"config,omitempty"`
 	Prefix         string    `yaml:"prefix,omitempty" json:"prefix,omitempty"`
 	Metadata       *Metadata `yaml:"metadata,omitempty" json:"metadata,omitempty"`
+	// MinGatewayVersion is the oldest gateway version able to run this server, e.g. v0.30.0
+	MinGatewayVersion string `yaml:"minGatewayVersion,omitempty" json:"minGatewayVersion,omitempty"`
+	// Members are the servers of a bundle, enabled together
+	Members []string `yaml:"members,omitempty" json:"members,omitempty"`
 }
 
 type Metadata struct {
@@ -52,10 +59,61 @@ func (s *Server) IsOAuthServer() bool {
 	return s.OAuth != nil && len(s.OAuth.Providers) > 0
 }
 
+// IsBundle tells whether the entry groups other servers of the catalog instead of being a server.
+func (s *Server) IsBundle() bool {
+	return s.Type == "bundle"
+}
+
 func (s *Server) IsRemoteOAuthServer() bool {
 	return s.Type == "remote" && s.IsOAuthServer()
 }
 
+// ConfigItems returns the server's config schemas that are objects.
+func (s *Server) ConfigItems() []ConfigItem {
+	return ParseConfigItems(s.Config)
+}
+
+// ConfigItem is one of the config schemas of a server.
+type ConfigItem struct {
+	// Name identifies the config values validated by the schema. It can be empty.
+	Name string
+	// Schema is the JSON schema, as found in the catalog.
+	Schema map[string]any
+}
+
+// ParseConfigItems parses raw config schemas, skipping the ones that are not objects.
+func ParseConfigItems(config []any) []ConfigItem {
+	var items []ConfigItem
+	for _, configItem := range config {
+		schema, ok := configItem.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		name, _ := schema["name"].(string)
+		items = append(items, ConfigItem{
+			Name:   name,
+			Schema: schema,
+		})
+	}
+
+	return items
+}
+
+// Properties returns the schemas of the config item's properties that are objects, by key.
+func (c ConfigItem) Properties() map[string]map[string]any {
+	rawProperties, _ := c.Schema["properties"].(map[string]any)
+
+	properties := map[string]map[string]any{}
+	for key, rawProperty := range rawProperties {
+		if property, ok := rawProperty.(map[string]any); ok {
+			properties[key] = property
+		}
+	}
+
+	return properties
+}
+
 type Secret struct {
 	Name string `yaml:"name" json:"name"`
 	Env  string `yaml:"env" json:"env"`
@@ -162,4 +220,17 @@ type ServerConfig struct {
 	Spec    Server
 	Config  map[string]any
 	Secrets map[string]string
+
+	// Site-specific settings for the server's container, from the registry
+	ExtraRunArgs []string
+	ExtraEnv     map[string]string
+	ExtraVolumes []VolumeMount
+	Network      string
+}
+
+// VolumeMount is a host directory mounted into a server's container
+type VolumeMount struct {
+	HostPath      string `yaml:"hostPath" json:"hostPath"`
+	ContainerPath string `yaml:"containerPath" json:"containerPath"`
+	ReadOnly      bool   `yaml:"readOnly,omitempty" json:"readOnly,omitempty"`
 }
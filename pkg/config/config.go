@@ -2,6 +2,10 @@ package config
 
 import "gopkg.in/yaml.v3"
 
+// SecretsCaseInsensitiveEnv, when set to 1, makes the names of the secrets stored in the
+// secrets files case-insensitive.
+const SecretsCaseInsensitiveEnv = "DOCKER_MCP_SECRETS_CASE_INSENSITIVE"
+
 func ParseConfig(configYaml []byte) (map[string]map[string]any, error) {
 	var config map[string]map[string]any
 	if err := yaml.Unmarshal(configYaml, &config); err != nil {
@@ -3,15 +3,25 @@ package config
 import (
 	"context"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/user"
 )
 
+// StdinPath is the path used to read a configuration file from stdin.
+const StdinPath = "-"
+
+// remoteClient fetches the configuration files from URLs. A server that doesn't answer can't
+// block the gateway.
+var remoteClient = &http.Client{Timeout: 30 * time.Second}
+
 func ReadTools(ctx context.Context, docker docker.Client) ([]byte, error) {
 	return ReadConfigFile(ctx, docker, "tools.yaml")
 }
@@ -100,6 +110,39 @@ func ReadConfigFile(ctx context.Context, docker docker.Client, name string) ([]b
 	return buf, nil
 }
 
+// IsRemote returns true if the configuration file is read from stdin or fetched from an http(s) URL.
+func IsRemote(name string) bool {
+	return name == StdinPath || isURL(name)
+}
+
+// ReadRemote reads a configuration file from stdin or fetches it from an http(s) URL.
+func ReadRemote(ctx context.Context, name string) ([]byte, error) {
+	if name == StdinPath {
+		return io.ReadAll(os.Stdin)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, name, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := remoteClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch URL: %s, status: %s", name, resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func isURL(name string) bool {
+	return strings.HasPrefix(name, "http://") || strings.HasPrefix(name, "https://")
+}
+
 func writeConfigFile(name string, content []byte) error {
 	path, err := FilePath(name)
 	if err != nil {
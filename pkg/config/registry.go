@@ -4,6 +4,8 @@ import (
 	"sort"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
 )
 
 type Registry struct {
@@ -11,8 +13,25 @@ type Registry struct {
 }
 
 type Tile struct {
-	Ref    string         `yaml:"ref"`
-	Config map[string]any `yaml:"config,omitempty"`
+	Ref        string         `yaml:"ref"`
+	Config     map[string]any `yaml:"config,omitempty"`
+	AllowTools []string       `yaml:"allowTools,omitempty"` // If not empty, only these tools are exposed
+	DenyTools  []string       `yaml:"denyTools,omitempty"`  // These tools are never exposed, even if allowed
+	Prefix     string         `yaml:"prefix,omitempty"`     // Prefix of the tool names, overrides the catalog's
+	DependsOn  []string       `yaml:"dependsOn,omitempty"`  // Servers started, and listed, before this one
+
+	CacheableTools []string `yaml:"cacheableTools,omitempty"` // Pure tools whose results are cached, see --tool-cache-ttl
+
+	AlwaysOnTools []string `yaml:"alwaysOnTools,omitempty"` // Listed to the client even with --discoverable-tools
+
+	ToolDescriptions map[string]string `yaml:"toolDescriptions,omitempty"` // Override the tools' own descriptions, by tool name
+
+	// Site-specific settings for the server's container
+	ExtraRunArgs []string              `yaml:"extraRunArgs,omitempty"` // Added to docker run, e.g. --dns
+	ExtraEnv     map[string]string     `yaml:"extraEnv,omitempty"`     // Added to the container's environment
+	EnvFile      string                `yaml:"envFile,omitempty"`      // .env file added to the container's environment, before extraEnv
+	Volumes      []catalog.VolumeMount `yaml:"volumes,omitempty"`      // Host directories mounted into the container
+	Network      string                `yaml:"network,omitempty"`      // none, bridge or the name of a docker network
 }
 
 func ParseRegistryConfig(registryYaml []byte) (Registry, error) {
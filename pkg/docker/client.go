@@ -33,6 +33,7 @@ type Client interface {
 	CreateNetwork(ctx context.Context, name string, internal bool, labels map[string]string) error
 	RemoveNetwork(ctx context.Context, name string) error
 	ConnectNetwork(ctx context.Context, networkName, container, hostname string) error
+	NetworkExists(ctx context.Context, name string) (bool, error)
 	InspectVolume(ctx context.Context, name string) (volume.Volume, error)
 	ReadSecrets(ctx context.Context, names []string, lenient bool) (map[string]string, error)
 }
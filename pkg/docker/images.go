@@ -24,10 +24,33 @@ func (c *dockerClient) ImageExists(ctx context.Context, name string) (bool, erro
 	return err == nil, err
 }
 
+// registryAuthKey is the key of the registry auth lookup shared by the pulls of a context.
+type registryAuthKey struct{}
+
+// lookupRegistryAuth gets the registry auth from Docker Desktop. Replaced in tests.
+var lookupRegistryAuth = getRegistryAuth
+
+// WithSharedRegistryAuth returns a context in which PullImage looks the registry auth up only
+// once, for all the pulls, as PullImages does.
+func WithSharedRegistryAuth(ctx context.Context) context.Context {
+	return context.WithValue(ctx, registryAuthKey{}, sync.OnceValue(func() string {
+		return lookupRegistryAuth(ctx)
+	}))
+}
+
+// registryAuthFunc returns the registry auth lookup shared by the pulls of a context, if any,
+// or a lookup of its own.
+func registryAuthFunc(ctx context.Context) func() string {
+	if registryAuthFn, ok := ctx.Value(registryAuthKey{}).(func() string); ok {
+		return registryAuthFn
+	}
+	return func() string {
+		return lookupRegistryAuth(ctx)
+	}
+}
+
 func (c *dockerClient) PullImages(ctx context.Context, names ...string) error {
-	registryAuthFn := sync.OnceValue(func() string {
-		return getRegistryAuth(ctx)
-	})
+	registryAuthFn := registryAuthFunc(WithSharedRegistryAuth(ctx))
 
 	errs, ctx := errgroup.WithContext(ctx)
 	errs.SetLimit(runtime.NumCPU())
@@ -42,9 +65,7 @@ func (c *dockerClient) PullImages(ctx context.Context, names ...string) error {
 }
 
 func (c *dockerClient) PullImage(ctx context.Context, name string) error {
-	return c.pullImage(ctx, name, func() string {
-		return getRegistryAuth(ctx)
-	})
+	return c.pullImage(ctx, name, registryAuthFunc(ctx))
 }
 
 func (c *dockerClient) InspectImage(ctx context.Context, name string) (image.InspectResponse, error) {
@@ -0,0 +1,50 @@
+package docker
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func countRegistryAuthLookups(t *testing.T) *atomic.Int32 {
+	t.Helper()
+
+	var lookups atomic.Int32
+	previous := lookupRegistryAuth
+	lookupRegistryAuth = func(context.Context) string {
+		lookups.Add(1)
+		return "auth"
+	}
+	t.Cleanup(func() { lookupRegistryAuth = previous })
+
+	return &lookups
+}
+
+func TestSharedRegistryAuth(t *testing.T) {
+	lookups := countRegistryAuthLookups(t)
+	ctx := WithSharedRegistryAuth(t.Context())
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, "auth", registryAuthFunc(ctx)())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), lookups.Load())
+}
+
+func TestRegistryAuthNotShared(t *testing.T) {
+	lookups := countRegistryAuthLookups(t)
+
+	assert.Equal(t, "auth", registryAuthFunc(t.Context())())
+	assert.Equal(t, "auth", registryAuthFunc(t.Context())())
+
+	assert.Equal(t, int32(2), lookups.Load())
+}
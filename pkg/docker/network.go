@@ -3,6 +3,7 @@ package docker
 import (
 	"context"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/network"
 )
 
@@ -27,3 +28,12 @@ func (c *dockerClient) ConnectNetwork(ctx context.Context, networkName, containe
 		Aliases: []string{hostname},
 	})
 }
+
+func (c *dockerClient) NetworkExists(ctx context.Context, name string) (bool, error) {
+	_, err := c.apiClient().NetworkInspect(ctx, name, network.InspectOptions{})
+	if cerrdefs.IsNotFound(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
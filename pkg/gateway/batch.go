@@ -0,0 +1,233 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// mcpSessionIDHeader is the header holding the session of the streamable HTTP transport
+const mcpSessionIDHeader = "Mcp-Session-Id"
+
+// jsonRPCInvalidRequest is the JSON-RPC error code of requests that can't be processed
+const jsonRPCInvalidRequest = -32600
+
+// maxBatchSize is the largest batch accepted by the batchHandler. The single messages are not
+// read by the batchHandler, they are left to the streamable HTTP transport.
+const maxBatchSize = 4 << 20
+
+// batchHandler accepts JSON-RPC batches on the streamable HTTP transport, which only supports them
+// for old protocol versions. Each message of the batch is dispatched, in order, as a request of its
+// own, so quotas and timeouts apply to every tool call. The responses are returned as an array in
+// the order of the requests. Notifications get no response.
+func batchHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Only the start of the body tells a batch from a single message
+		reader := bufio.NewReader(r.Body)
+		body := struct {
+			io.Reader
+			io.Closer
+		}{reader, r.Body}
+		if !isBatch(reader) {
+			r.Body = body
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		batch, err := io.ReadAll(http.MaxBytesReader(w, body, maxBatchSize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				http.Error(w, "batch too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+
+		var messages []json.RawMessage
+		if err := json.Unmarshal(batch, &messages); err != nil {
+			http.Error(w, "malformed batch: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(messages) == 0 {
+			http.Error(w, "empty batch", http.StatusBadRequest)
+			return
+		}
+
+		sessionID := r.Header.Get(mcpSessionIDHeader)
+		var responses []json.RawMessage
+		for _, message := range messages {
+			var envelope struct {
+				ID json.RawMessage `json:"id"`
+			}
+			_ = json.Unmarshal(message, &envelope)
+
+			rec := newResponseCapture()
+			next.ServeHTTP(rec, batchMessageRequest(r, message, sessionID))
+
+			// An initialize request in the batch starts the session of the next messages
+			if id := rec.header.Get(mcpSessionIDHeader); id != "" {
+				sessionID = id
+				w.Header().Set(mcpSessionIDHeader, id)
+			}
+
+			if len(envelope.ID) == 0 || string(envelope.ID) == "null" {
+				continue
+			}
+			responses = append(responses, batchMessageResponse(rec, envelope.ID))
+		}
+
+		if len(responses) == 0 {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		buf, err := json.Marshal(responses)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(buf)
+	})
+}
+
+// isBatch skips the whitespace at the start of the body and tells whether a JSON array comes next.
+func isBatch(reader *bufio.Reader) bool {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return false
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		_ = reader.UnreadByte()
+		return b == '['
+	}
+}
+
+// responseCapture keeps the response to one message of a batch in memory.
+type responseCapture struct {
+	header http.Header
+	code   int
+	body   bytes.Buffer
+}
+
+func newResponseCapture() *responseCapture {
+	return &responseCapture{
+		header: http.Header{},
+		code:   http.StatusOK,
+	}
+}
+
+func (c *responseCapture) Header() http.Header {
+	return c.header
+}
+
+func (c *responseCapture) Write(buf []byte) (int, error) {
+	return c.body.Write(buf)
+}
+
+func (c *responseCapture) WriteHeader(code int) {
+	c.code = code
+}
+
+// Flush is a no-op, the response is only read once complete. The event streams require it.
+func (c *responseCapture) Flush() {}
+
+// batchMessageRequest is a copy of the batch's HTTP request, carrying a single message.
+func batchMessageRequest(r *http.Request, message json.RawMessage, sessionID string) *http.Request {
+	req := r.Clone(r.Context())
+	req.Body = io.NopCloser(bytes.NewReader(message))
+	req.ContentLength = int64(len(message))
+	req.Header.Set("Content-Length", strconv.Itoa(len(message)))
+	if sessionID != "" {
+		req.Header.Set(mcpSessionIDHeader, sessionID)
+	}
+	return req
+}
+
+// batchMessageResponse extracts the response to a request from a JSON or an event stream
+// HTTP response. HTTP errors are turned into JSON-RPC errors.
+func batchMessageResponse(rec *responseCapture, id json.RawMessage) json.RawMessage {
+	if rec.code < http.StatusOK || rec.code >= http.StatusMultipleChoices {
+		return jsonRPCError(id, strings.TrimSpace(rec.body.String()))
+	}
+
+	var candidates []json.RawMessage
+	if strings.HasPrefix(rec.header.Get("Content-Type"), "text/event-stream") {
+		candidates = eventStreamData(rec.body.Bytes())
+	} else {
+		candidates = []json.RawMessage{rec.body.Bytes()}
+	}
+
+	// Skip the notifications and the server's requests sent before the response
+	for _, candidate := range candidates {
+		var response struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.Unmarshal(candidate, &response); err == nil && response.Method == "" && bytes.Equal(response.ID, id) {
+			return candidate
+		}
+	}
+
+	return jsonRPCError(id, "no response")
+}
+
+// eventStreamData returns the data of each event of a text/event-stream body.
+func eventStreamData(body []byte) []json.RawMessage {
+	var (
+		events []json.RawMessage
+		data   []string
+	)
+	flush := func() {
+		if len(data) > 0 {
+			events = append(events, json.RawMessage(strings.Join(data, "\n")))
+			data = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, len(body)+1)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "data:"); ok {
+			data = append(data, strings.TrimPrefix(value, " "))
+		}
+	}
+	flush()
+
+	return events
+}
+
+func jsonRPCError(id json.RawMessage, message string) json.RawMessage {
+	buf, _ := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]any{
+			"code":    jsonRPCInvalidRequest,
+			"message": message,
+		},
+	})
+	return buf
+}
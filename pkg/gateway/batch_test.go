@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	server.AddTool(&mcp.Tool{Name: "echo", InputSchema: &jsonschema.Schema{Type: "object"}}, func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(req.Params.Arguments)}}}, nil
+	})
+
+	handler := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil)
+	httpServer := httptest.NewServer(batchHandler(handler))
+	t.Cleanup(httpServer.Close)
+	return httpServer
+}
+
+func postMCP(t *testing.T, url, sessionID, body string) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	req.Header.Set("Mcp-Protocol-Version", "2025-06-18")
+	if sessionID != "" {
+		req.Header.Set(mcpSessionIDHeader, sessionID)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestBatchHandler(t *testing.T) {
+	server := newBatchTestServer(t)
+
+	batch := `[
+		{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}},
+		{"jsonrpc":"2.0","method":"notifications/initialized"},
+		{"jsonrpc":"2.0","id":"second","method":"tools/call","params":{"name":"echo","arguments":{"n":2}}},
+		{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"echo","arguments":{"n":3}}}
+	]`
+	resp := postMCP(t, server.URL, "", batch)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotEmpty(t, resp.Header.Get(mcpSessionIDHeader))
+
+	var responses []struct {
+		ID     json.RawMessage `json:"id"`
+		Result struct {
+			Content []struct {
+				Text string `json:"text"`
+			} `json:"content"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&responses))
+
+	require.Len(t, responses, 3)
+	assert.JSONEq(t, `1`, string(responses[0].ID))
+	assert.JSONEq(t, `"second"`, string(responses[1].ID))
+	assert.JSONEq(t, `{"n":2}`, responses[1].Result.Content[0].Text)
+	assert.JSONEq(t, `3`, string(responses[2].ID))
+	assert.JSONEq(t, `{"n":3}`, responses[2].Result.Content[0].Text)
+}
+
+func TestBatchHandlerOnlyNotifications(t *testing.T) {
+	server := newBatchTestServer(t)
+
+	resp := postMCP(t, server.URL, "", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	sessionID := resp.Header.Get(mcpSessionIDHeader)
+
+	resp = postMCP(t, server.URL, sessionID, `[{"jsonrpc":"2.0","method":"notifications/initialized"}]`)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Empty(t, body)
+}
+
+func TestBatchHandlerEmptyBatch(t *testing.T) {
+	server := newBatchTestServer(t)
+
+	resp := postMCP(t, server.URL, "", `[]`)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestBatchHandlerBodyTooLarge(t *testing.T) {
+	server := newBatchTestServer(t)
+
+	resp := postMCP(t, server.URL, "", `[`+strings.Repeat(" ", maxBatchSize)+`]`)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestBatchHandlerSingleMessageNotLimited(t *testing.T) {
+	server := newBatchTestServer(t)
+
+	// The limit of the batches doesn't apply to the single messages
+	resp := postMCP(t, server.URL, "", "\n  "+`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test","version":"1.0"}}}`+strings.Repeat(" ", maxBatchSize))
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestEventStreamData(t *testing.T) {
+	body := "event: message\nid: 1\ndata: {\"method\":\"notifications/progress\"}\n\nevent: message\ndata: {\"id\":1,\ndata: \"result\":{}}\n\n"
+
+	events := eventStreamData([]byte(body))
+
+	require.Len(t, events, 2)
+	assert.JSONEq(t, `{"method":"notifications/progress"}`, string(events[0]))
+	assert.JSONEq(t, `{"id":1,"result":{}}`, string(events[1]))
+}
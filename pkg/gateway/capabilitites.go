@@ -3,7 +3,6 @@ package gateway
 import (
 	"context"
 	"fmt"
-	"runtime"
 	"slices"
 	"strings"
 	"sync"
@@ -28,6 +27,37 @@ type ToolRegistration struct {
 	ServerName string
 	Tool       *mcp.Tool
 	Handler    mcp.ToolHandler
+	AlwaysOn   bool // Listed to the client even with --discoverable-tools
+	Cacheable  bool // Results cached for --tool-cache-ttl
+}
+
+// toolCollision is a tool name exposed by more than one server
+type toolCollision struct {
+	ToolName    string
+	ServerNames []string
+}
+
+// findToolCollisions returns the tool names registered by more than one server, sorted by name.
+func findToolCollisions(tools []ToolRegistration) []toolCollision {
+	serversByTool := map[string][]string{}
+	for _, tool := range tools {
+		if !slices.Contains(serversByTool[tool.Tool.Name], tool.ServerName) {
+			serversByTool[tool.Tool.Name] = append(serversByTool[tool.Tool.Name], tool.ServerName)
+		}
+	}
+
+	var collisions []toolCollision
+	for toolName, serverNames := range serversByTool {
+		if len(serverNames) > 1 {
+			slices.Sort(serverNames)
+			collisions = append(collisions, toolCollision{ToolName: toolName, ServerNames: serverNames})
+		}
+	}
+	slices.SortFunc(collisions, func(a, b toolCollision) int {
+		return strings.Compare(a.ToolName, b.ToolName)
+	})
+
+	return collisions
 }
 
 type PromptRegistration struct {
@@ -58,17 +88,28 @@ func (caps *Capabilities) getToolByName(toolName string) (ToolRegistration, erro
 }
 
 // getToolNamePrefix returns the prefix to use for tool names based on server configuration
-// and gateway options. If ServerSpec.Prefix is set, it always uses that. Otherwise, it
-// uses the server name if ToolNamePrefix feature flag is enabled.
-func (g *Gateway) getToolNamePrefix(serverConfig *catalog.ServerConfig) string {
+// and gateway options.
+func (g *Gateway) getToolNamePrefix(configuration Configuration, serverConfig *catalog.ServerConfig) string {
+	return toolNamePrefix(configuration, serverConfig.Name, serverConfig.Spec, g.ToolNamePrefix)
+}
+
+// toolNamePrefix returns the prefix of the tools of a server. A prefix set in the registry
+// takes precedence over ServerSpec.Prefix. If none is set, it uses the server name if
+// byServerName is enabled.
+func toolNamePrefix(configuration Configuration, serverName string, server catalog.Server, byServerName bool) string {
+	// If explicit prefix is set in the registry, always use it
+	if prefix := configuration.registry[serverName].Prefix; prefix != "" {
+		return prefix
+	}
+
 	// If explicit prefix is set in server config, always use it
-	if serverConfig.Spec.Prefix != "" {
-		return serverConfig.Spec.Prefix
+	if server.Prefix != "" {
+		return server.Prefix
 	}
 
 	// Otherwise, use server name if tool-name-prefix feature is enabled
-	if g.ToolNamePrefix {
-		return serverConfig.Name
+	if byServerName {
+		return serverName
 	}
 
 	// No prefix
@@ -111,19 +152,74 @@ func (caps *Capabilities) getResourceTemplateByURITemplate(resource string) (Res
 }
 
 func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, clientConfig *clientConfig) (*Capabilities, error) {
+	// Servers are started after the servers they depend on
+	waves, err := startupOrder(serverNames, g.currentConfiguration().registry)
+	if err != nil {
+		return nil, err
+	}
+
+	var allCapabilities []Capabilities
+	for _, wave := range waves {
+		var startable []string
+		for _, serverName := range wave {
+			if dependency, unavailable := g.unavailableDependency(serverName); unavailable {
+				log.Logf("  > Can't start %s: dependency %s is not available", serverName, dependency)
+				g.degraded.fail(serverName, fmt.Errorf("dependency %s is not available", dependency))
+				continue
+			}
+			startable = append(startable, serverName)
+		}
+
+		capabilities, err := g.listWaveCapabilities(ctx, startable, clientConfig)
+		if err != nil {
+			return nil, err
+		}
+		allCapabilities = append(allCapabilities, capabilities...)
+	}
+
+	// Merge all capabilities
+	var allTools []ToolRegistration
+	var allPrompts []PromptRegistration
+	var allResources []ResourceRegistration
+	var allResourceTemplates []ResourceTemplateRegistration
+	for _, capabilities := range allCapabilities {
+		allTools = append(allTools, capabilities.Tools...)
+		allPrompts = append(allPrompts, capabilities.Prompts...)
+		allResources = append(allResources, capabilities.Resources...)
+		allResourceTemplates = append(allResourceTemplates, capabilities.ResourceTemplates...)
+	}
+
+	return &Capabilities{
+		Tools:             allTools,
+		Prompts:           allPrompts,
+		Resources:         allResources,
+		ResourceTemplates: allResourceTemplates,
+	}, nil
+}
+
+// listWaveCapabilities starts servers concurrently and lists their capabilities.
+func (g *Gateway) listWaveCapabilities(ctx context.Context, serverNames []string, clientConfig *clientConfig) ([]Capabilities, error) {
 	var (
 		lock            sync.Mutex
 		allCapabilities []Capabilities
 	)
 
+	configuration := g.currentConfiguration()
+
 	errs, ctx := errgroup.WithContext(ctx)
-	errs.SetLimit(runtime.NumCPU())
+	errs.SetLimit(g.maxConcurrentLaunches())
 	for _, serverName := range serverNames {
-		serverConfig, toolGroup, found := g.configuration.Find(serverName)
+		serverConfig, toolGroup, found := configuration.Find(serverName)
 
 		switch {
 		case !found:
 			log.Log("  - MCP server not found:", serverName)
+			g.degraded.fail(serverName, fmt.Errorf("MCP server not found in the catalog"))
+
+		case configuration.envFileErrs[serverName] != nil:
+			err := configuration.envFileErrs[serverName]
+			log.Logf("  > Can't start %s: %s", serverName, err)
+			g.degraded.fail(serverName, err)
 
 		// It's an MCP Server
 		case serverConfig != nil:
@@ -131,6 +227,7 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 				client, err := g.clientPool.AcquireClient(ctx, serverConfig, clientConfig)
 				if err != nil {
 					log.Logf("  > Can't start %s: %s", serverConfig.Name, err)
+					g.degraded.fail(serverConfig.Name, fmt.Errorf("can't start: %w", err))
 					return nil
 				}
 				defer g.clientPool.ReleaseClient(client)
@@ -140,26 +237,32 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 				tools, err := client.Session().ListTools(ctx, &mcp.ListToolsParams{})
 				if err != nil {
 					log.Logf("  > Can't list tools %s: %s", serverConfig.Name, err)
+					g.degraded.fail(serverConfig.Name, fmt.Errorf("can't list tools: %w", err))
 				} else {
+					g.degraded.recover(serverConfig.Name)
+
 					// Record the number of tools discovered from this server
 					telemetry.RecordToolList(ctx, serverConfig.Name, len(tools.Tools))
 
 					// Determine the prefix to use for this server's tools
-					prefix := g.getToolNamePrefix(serverConfig)
+					prefix := g.getToolNamePrefix(configuration, serverConfig)
 
 					for _, tool := range tools.Tools {
-						if !isToolEnabled(g.configuration, serverConfig.Name, serverConfig.Spec.Image, tool.Name, g.ToolNames) {
+						if !isToolEnabled(configuration, serverConfig.Name, serverConfig.Spec.Image, tool.Name, g.ToolNames) {
 							continue
 						}
 
 						// Create a copy of the tool and apply prefix to its name
 						prefixedTool := *tool
 						prefixedTool.Name = prefixToolName(prefix, tool.Name)
+						prefixedTool.Description = toolDescription(configuration, serverConfig.Name, tool.Name, tool.Description)
 
 						capabilities.Tools = append(capabilities.Tools, ToolRegistration{
 							ServerName: serverConfig.Name,
 							Tool:       &prefixedTool,
-							Handler:    g.mcpServerToolHandler(serverConfig.Name, g.mcpServer, tool.Annotations),
+							Handler:    withToolTimeout(prefixedTool.Name, g.ToolCallTimeout, g.mcpServerToolHandler(serverConfig.Name, g.mcpServer, tool.Annotations)),
+							AlwaysOn:   isToolAlwaysOn(configuration, serverConfig.Name, tool.Name, g.AlwaysOnTools),
+							Cacheable:  isToolCacheable(configuration, serverConfig.Name, tool.Name),
 						})
 					}
 				}
@@ -234,14 +337,14 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 		case toolGroup != nil:
 			var capabilities Capabilities
 
-			// For POCI tools, use server name as prefix if feature flag is enabled
-			var prefix string
-			if g.ToolNamePrefix {
+			// For POCI tools, use the registry's prefix, or the server name if ToolNamePrefix is enabled
+			prefix := configuration.registry[serverName].Prefix
+			if prefix == "" && g.ToolNamePrefix {
 				prefix = serverName
 			}
 
 			for _, tool := range *toolGroup {
-				if !isToolEnabled(g.configuration, serverName, "", tool.Name, g.ToolNames) {
+				if !isToolEnabled(configuration, serverName, "", tool.Name, g.ToolNames) {
 					continue
 				}
 
@@ -260,13 +363,16 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 
 				mcpTool := mcp.Tool{
 					Name:        prefixToolName(prefix, tool.Name),
-					Description: tool.Description,
+					Description: toolDescription(configuration, serverName, tool.Name, tool.Description),
 					InputSchema: schema,
 				}
 
 				capabilities.Tools = append(capabilities.Tools, ToolRegistration{
-					Tool:    &mcpTool,
-					Handler: g.mcpToolHandler(tool),
+					ServerName: serverName,
+					Tool:       &mcpTool,
+					Handler:    withToolTimeout(mcpTool.Name, g.ToolCallTimeout, g.mcpToolHandler(tool)),
+					AlwaysOn:   isToolAlwaysOn(configuration, serverName, tool.Name, g.AlwaysOnTools),
+					Cacheable:  isToolCacheable(configuration, serverName, tool.Name),
 				})
 			}
 
@@ -280,24 +386,7 @@ func (g *Gateway) listCapabilities(ctx context.Context, serverNames []string, cl
 		return nil, err
 	}
 
-	// Merge all capabilities
-	var allTools []ToolRegistration
-	var allPrompts []PromptRegistration
-	var allResources []ResourceRegistration
-	var allResourceTemplates []ResourceTemplateRegistration
-	for _, capabilities := range allCapabilities {
-		allTools = append(allTools, capabilities.Tools...)
-		allPrompts = append(allPrompts, capabilities.Prompts...)
-		allResources = append(allResources, capabilities.Resources...)
-		allResourceTemplates = append(allResourceTemplates, capabilities.ResourceTemplates...)
-	}
-
-	return &Capabilities{
-		Tools:             allTools,
-		Prompts:           allPrompts,
-		Resources:         allResources,
-		ResourceTemplates: allResourceTemplates,
-	}, nil
+	return allCapabilities, nil
 }
 
 func (caps *Capabilities) ToolNames() []string {
@@ -317,6 +406,10 @@ func (caps *Capabilities) PromptNames() []string {
 }
 
 func isToolEnabled(configuration Configuration, serverName, serverImage, toolName string, enabledTools []string) bool {
+	if !isToolAllowed(configuration, serverName, toolName) {
+		return false
+	}
+
 	if len(enabledTools) == 0 {
 		tools, exists := configuration.tools.ServerTools[serverName]
 		if !exists {
@@ -347,3 +440,78 @@ func isToolEnabled(configuration Configuration, serverName, serverImage, toolNam
 
 	return false
 }
+
+// isToolAllowed applies the allow and deny lists of the server's registry entry.
+// Deny takes precedence over allow.
+func isToolAllowed(configuration Configuration, serverName, toolName string) bool {
+	tile, exists := configuration.registry[serverName]
+	if !exists {
+		return true
+	}
+
+	if slices.Contains(tile.DenyTools, toolName) {
+		return false
+	}
+
+	return len(tile.AllowTools) == 0 || slices.Contains(tile.AllowTools, toolName)
+}
+
+// isToolAlwaysOn tells whether a tool is pinned by the server's registry entry or by --always-on-tools,
+// which accepts the same formats as --tools.
+func isToolAlwaysOn(configuration Configuration, serverName, toolName string, alwaysOnTools []string) bool {
+	if slices.Contains(configuration.registry[serverName].AlwaysOnTools, toolName) {
+		return true
+	}
+
+	for _, alwaysOn := range alwaysOnTools {
+		if strings.EqualFold(alwaysOn, toolName) ||
+			strings.EqualFold(alwaysOn, serverName+":"+toolName) ||
+			strings.EqualFold(alwaysOn, serverName+":*") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// toolDescription returns the description of a tool set by the server's registry entry, if any,
+// or the tool's own description.
+func toolDescription(configuration Configuration, serverName, toolName, description string) string {
+	if override := configuration.registry[serverName].ToolDescriptions[toolName]; override != "" {
+		return override
+	}
+
+	return description
+}
+
+// withToolDescriptions returns a copy of a catalog server whose tools use the descriptions
+// set by the server's registry entry, so that mcp-find matches them.
+func withToolDescriptions(configuration Configuration, serverName string, server catalog.Server) catalog.Server {
+	if len(configuration.registry[serverName].ToolDescriptions) == 0 {
+		return server
+	}
+
+	server.Tools = slices.Clone(server.Tools)
+	for i, tool := range server.Tools {
+		server.Tools[i].Description = toolDescription(configuration, serverName, tool.Name, tool.Description)
+	}
+
+	return server
+}
+
+// GetToolRegistrationsSorted returns the tools currently exposed by the gateway, sorted by name.
+func (g *Gateway) GetToolRegistrationsSorted() []*ToolRegistration {
+	g.capabilitiesMu.RLock()
+	defer g.capabilitiesMu.RUnlock()
+
+	registrations := make([]*ToolRegistration, 0, len(g.toolRegistrations))
+	for _, toolReg := range g.toolRegistrations {
+		registrations = append(registrations, &toolReg)
+	}
+
+	slices.SortFunc(registrations, func(a, b *ToolRegistration) int {
+		return strings.Compare(a.Tool.Name, b.Tool.Name)
+	})
+
+	return registrations
+}
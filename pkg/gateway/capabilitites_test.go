@@ -0,0 +1,122 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+func TestIsToolEnabledAllowDenyLists(t *testing.T) {
+	configuration := Configuration{
+		registry: map[string]config.Tile{
+			"github": {
+				Ref:        "github",
+				AllowTools: []string{"get_me", "create_issue", "delete_repository"},
+				DenyTools:  []string{"delete_repository"},
+			},
+			"slack": {
+				Ref:       "slack",
+				DenyTools: []string{"post_message"},
+			},
+		},
+	}
+
+	assert.True(t, isToolEnabled(configuration, "github", "", "get_me", nil))
+	assert.False(t, isToolEnabled(configuration, "github", "", "list_repositories", nil))
+	assert.False(t, isToolEnabled(configuration, "github", "", "delete_repository", nil))
+	assert.False(t, isToolEnabled(configuration, "github", "", "delete_repository", []string{"*"}))
+
+	assert.True(t, isToolEnabled(configuration, "slack", "", "list_channels", nil))
+	assert.False(t, isToolEnabled(configuration, "slack", "", "post_message", nil))
+
+	assert.True(t, isToolEnabled(configuration, "fetch", "", "fetch", nil))
+}
+
+func TestGetToolNamePrefix(t *testing.T) {
+	configuration := Configuration{
+		registry: map[string]config.Tile{
+			"github": {Ref: "github", Prefix: "gh"},
+		},
+	}
+	g := &Gateway{}
+
+	assert.Equal(t, "gh", g.getToolNamePrefix(configuration, &catalog.ServerConfig{Name: "github", Spec: catalog.Server{Prefix: "git"}}))
+	assert.Equal(t, "ddg", g.getToolNamePrefix(configuration, &catalog.ServerConfig{Name: "duckduckgo", Spec: catalog.Server{Prefix: "ddg"}}))
+	assert.Empty(t, g.getToolNamePrefix(configuration, &catalog.ServerConfig{Name: "fetch"}))
+
+	g.ToolNamePrefix = true
+	assert.Equal(t, "fetch", g.getToolNamePrefix(configuration, &catalog.ServerConfig{Name: "fetch"}))
+}
+
+func TestFindToolCollisions(t *testing.T) {
+	tools := []ToolRegistration{
+		{ServerName: "duckduckgo", Tool: &mcp.Tool{Name: "search"}},
+		{ServerName: "brave", Tool: &mcp.Tool{Name: "search"}},
+		{ServerName: "brave", Tool: &mcp.Tool{Name: "summarize"}},
+		{ServerName: "fetch", Tool: &mcp.Tool{Name: "fetch"}},
+		{ServerName: "curl", Tool: &mcp.Tool{Name: "fetch"}},
+	}
+
+	assert.Equal(t, []toolCollision{
+		{ToolName: "fetch", ServerNames: []string{"curl", "fetch"}},
+		{ToolName: "search", ServerNames: []string{"brave", "duckduckgo"}},
+	}, findToolCollisions(tools))
+
+	assert.Empty(t, findToolCollisions(tools[2:4]))
+}
+
+func TestIsToolAlwaysOn(t *testing.T) {
+	configuration := Configuration{
+		registry: map[string]config.Tile{
+			"github": {Ref: "github", AlwaysOnTools: []string{"get_me"}},
+		},
+	}
+
+	assert.True(t, isToolAlwaysOn(configuration, "github", "get_me", nil))
+	assert.False(t, isToolAlwaysOn(configuration, "github", "list_issues", nil))
+
+	assert.True(t, isToolAlwaysOn(configuration, "github", "list_issues", []string{"github:list_issues"}))
+	assert.True(t, isToolAlwaysOn(configuration, "fetch", "fetch", []string{"fetch:*"}))
+	assert.True(t, isToolAlwaysOn(configuration, "fetch", "fetch", []string{"fetch"}))
+	assert.False(t, isToolAlwaysOn(configuration, "fetch", "fetch", []string{"github:*"}))
+}
+
+func TestToolDescription(t *testing.T) {
+	configuration := Configuration{
+		registry: map[string]config.Tile{
+			"github": {Ref: "github", ToolDescriptions: map[string]string{"get_me": "Get the profile of the authenticated GitHub user"}},
+		},
+	}
+
+	assert.Equal(t, "Get the profile of the authenticated GitHub user", toolDescription(configuration, "github", "get_me", "Get me"))
+	assert.Equal(t, "List issues", toolDescription(configuration, "github", "list_issues", "List issues"))
+	assert.Equal(t, "Fetch a URL", toolDescription(configuration, "fetch", "fetch", "Fetch a URL"))
+}
+
+func TestWithToolDescriptions(t *testing.T) {
+	configuration := Configuration{
+		registry: map[string]config.Tile{
+			"github": {Ref: "github", ToolDescriptions: map[string]string{"get_me": "Get the profile of the authenticated user"}},
+		},
+	}
+	server := catalog.Server{
+		Tools: []catalog.Tool{
+			{Name: "get_me", Description: "Get me"},
+			{Name: "list_issues", Description: "List issues"},
+		},
+	}
+
+	overridden := withToolDescriptions(configuration, "github", server)
+
+	assert.Equal(t, "Get the profile of the authenticated user", overridden.Tools[0].Description)
+	assert.Equal(t, "List issues", overridden.Tools[1].Description)
+	// The catalog is left untouched
+	assert.Equal(t, "Get me", server.Tools[0].Description)
+
+	assert.Positive(t, scoreServer("github", overridden, "profile").Total())
+	assert.Zero(t, scoreServer("github", server, "profile").Total())
+}
@@ -3,8 +3,10 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
 
@@ -37,6 +39,10 @@ type clientPool struct {
 	networks    []string
 	docker      docker.Client
 	gateway     *Gateway
+	// runPolicy restricts the docker run settings of the registry's server entries
+	runPolicy *runPolicy
+	// launches bounds the number of containers being started at the same time
+	launches chan struct{}
 }
 
 type clientConfig struct {
@@ -46,11 +52,18 @@ type clientConfig struct {
 }
 
 func newClientPool(options Options, docker docker.Client, gateway *Gateway) *clientPool {
+	var policy *runPolicy
+	if !options.AllowPrivilegedRunArgs {
+		policy = defaultRunPolicy()
+	}
+
 	return &clientPool{
 		Options:     options,
 		docker:      docker,
 		gateway:     gateway,
 		keptClients: make(map[clientKey]keptClient),
+		runPolicy:   policy,
+		launches:    make(chan struct{}, maxConcurrentLaunches(options)),
 	}
 }
 
@@ -123,6 +136,12 @@ func (cp *clientPool) AcquireClient(ctx context.Context, serverConfig *catalog.S
 	return client, nil
 }
 
+// StartDisposableClient starts a client bound to ctx that's never kept, even for a long-lived
+// server. The caller closes its session once done.
+func (cp *clientPool) StartDisposableClient(ctx context.Context, serverConfig *catalog.ServerConfig) (mcpclient.Client, error) {
+	return newClientGetter(serverConfig, cp, nil).GetClient(ctx)
+}
+
 func (cp *clientPool) ReleaseClient(client mcpclient.Client) {
 	foundKept := false
 	cp.clientLock.RLock()
@@ -218,22 +237,24 @@ func (cp *clientPool) runToolContainer(ctx context.Context, tool catalog.Tool, p
 	}
 
 	// Volumes
-	for _, mount := range eval.EvaluateList(tool.Container.Volumes, arguments) {
-		if mount == "" {
-			continue
-		}
-
+	volumes := nonEmpty(eval.EvaluateList(tool.Container.Volumes, arguments))
+	for _, mount := range volumes {
 		args = append(args, "-v", mount)
 	}
 
 	// User
+	var userVal string
 	if tool.Container.User != "" {
-		userVal := fmt.Sprintf("%v", eval.Evaluate(tool.Container.User, arguments))
+		userVal = fmt.Sprintf("%v", eval.Evaluate(tool.Container.User, arguments))
 		if userVal != "" {
 			args = append(args, "-u", userVal)
 		}
 	}
 
+	if err := cp.runPolicy.checkCatalogSettings(volumes, userVal); err != nil {
+		return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+	}
+
 	// Image
 	args = append(args, tool.Container.Image)
 
@@ -297,9 +318,12 @@ func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, readOnly *b
 	var env []string
 
 	// Security options
-	if serverConfig.Spec.DisableNetwork {
+	switch {
+	case serverConfig.Spec.DisableNetwork:
 		args = append(args, "--network", "none")
-	} else {
+	case serverConfig.Network != "":
+		args = append(args, "--network", serverConfig.Network)
+	default:
 		// Attach the MCP servers to the same network as the gateway.
 		for _, network := range cp.networks {
 			args = append(args, "--network", network)
@@ -347,11 +371,7 @@ func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, readOnly *b
 	}
 
 	// Volumes
-	for _, mount := range eval.EvaluateList(serverConfig.Spec.Volumes, serverConfig.Config) {
-		if mount == "" {
-			continue
-		}
-
+	for _, mount := range catalogVolumes(serverConfig) {
 		if readOnly != nil && *readOnly && !strings.HasSuffix(mount, ":ro") {
 			args = append(args, "-v", mount+":ro")
 		} else {
@@ -360,19 +380,51 @@ func (cp *clientPool) argsAndEnv(serverConfig *catalog.ServerConfig, readOnly *b
 	}
 
 	// User
-	if serverConfig.Spec.User != "" {
-		val := serverConfig.Spec.User
-		if strings.Contains(val, "{{") && strings.Contains(val, "}}") {
-			val = fmt.Sprintf("%v", eval.Evaluate(val, serverConfig.Config))
-		}
-		if val != "" {
-			args = append(args, "-u", val)
+	if val := catalogUser(serverConfig); val != "" {
+		args = append(args, "-u", val)
+	}
+
+	// Site-specific settings
+	for _, name := range slices.Sorted(maps.Keys(serverConfig.ExtraEnv)) {
+		args = append(args, "-e", name)
+		env = append(env, fmt.Sprintf("%s=%s", name, serverConfig.ExtraEnv[name]))
+	}
+	for _, mount := range serverConfig.ExtraVolumes {
+		if mount.ReadOnly || (readOnly != nil && *readOnly) {
+			args = append(args, "-v", mount.HostPath+":"+mount.ContainerPath+":ro")
+		} else {
+			args = append(args, "-v", mount.HostPath+":"+mount.ContainerPath)
 		}
 	}
+	args = append(args, serverConfig.ExtraRunArgs...)
 
 	return args, env
 }
 
+// catalogVolumes returns the volumes that the catalog mounts in a server's container.
+func catalogVolumes(serverConfig *catalog.ServerConfig) []string {
+	return nonEmpty(eval.EvaluateList(serverConfig.Spec.Volumes, serverConfig.Config))
+}
+
+// catalogUser returns the user that the catalog runs a server's container as, if any.
+func catalogUser(serverConfig *catalog.ServerConfig) string {
+	val := serverConfig.Spec.User
+	if strings.Contains(val, "{{") && strings.Contains(val, "}}") {
+		val = fmt.Sprintf("%v", eval.Evaluate(val, serverConfig.Config))
+	}
+	return val
+}
+
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, value := range values {
+		if value != "" {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
+
 func expandEnv(value string, env []string) string {
 	return os.Expand(value, func(name string) string {
 		for _, e := range env {
@@ -421,6 +473,7 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 			cleanup := func(context.Context) error { return nil }
 
 			var client mcpclient.Client
+			launched := false
 
 			// Deprecated: Use Remote instead
 			if cg.serverConfig.Spec.SSEEndpoint != "" {
@@ -438,6 +491,19 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 					}
 				}
 
+				if err := validateExtraRunArgs(cg.serverConfig.ExtraRunArgs, cg.cp.runPolicy); err != nil {
+					return nil, fmt.Errorf("server %s: %w", cg.serverConfig.Name, err)
+				}
+				if err := validateVolumeMounts(cg.serverConfig.ExtraVolumes, cg.cp.AllowedMountRoots); err != nil {
+					return nil, fmt.Errorf("server %s: %w", cg.serverConfig.Name, err)
+				}
+				if err := validateNetwork(ctx, cg.cp.docker, cg.serverConfig.Network, cg.cp.runPolicy); err != nil {
+					return nil, fmt.Errorf("server %s: %w", cg.serverConfig.Name, err)
+				}
+				if err := cg.cp.runPolicy.checkCatalogSettings(catalogVolumes(cg.serverConfig), catalogUser(cg.serverConfig)); err != nil {
+					return nil, fmt.Errorf("server %s: %w", cg.serverConfig.Name, err)
+				}
+
 				image := cg.serverConfig.Spec.Image
 				var readOnly *bool
 				if cg.clientConfig != nil {
@@ -458,6 +524,7 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 				runArgs = append(runArgs, command...)
 
 				client = mcpclient.NewStdioCmdClient(cg.serverConfig.Name, "docker", env, runArgs...)
+				launched = cg.cp.launches != nil
 			}
 
 			initParams := &mcp.InitializeParams{
@@ -477,8 +544,21 @@ func (cg *clientGetter) GetClient(ctx context.Context) (mcpclient.Client, error)
 			// ctx, cancel := context.WithTimeout(ctx, 20*time.Second)
 			// defer cancel()
 
+			// Don't start too many containers at once
+			if launched {
+				select {
+				case cg.cp.launches <- struct{}{}:
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			}
+
 			// TODO add initial roots
-			if err := client.Initialize(ctx, initParams, cg.cp.Verbose, ss, server, cg.cp.gateway); err != nil {
+			err := client.Initialize(ctx, initParams, cg.cp.Verbose, ss, server, cg.cp.gateway)
+			if launched {
+				<-cg.cp.launches
+			}
+			if err != nil {
 				return nil, err
 			}
 
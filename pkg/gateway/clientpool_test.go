@@ -255,3 +255,84 @@ func TestStdioClientInitialization(t *testing.T) {
 
 	t.Logf("Successfully initialized stdio client and retrieved %d tools", len(tools.Tools))
 }
+
+func TestApplyExtraRunArgsAndEnv(t *testing.T) {
+	clientPool := &clientPool{}
+
+	args, env := clientPool.argsAndEnv(&catalog.ServerConfig{
+		Name:         "svc",
+		ExtraRunArgs: []string{"--dns", "10.0.0.2", "-l", "site=paris"},
+		ExtraEnv:     map[string]string{"HTTPS_PROXY": "http://proxy:3128", "NO_PROXY": "localhost"},
+	}, nil, proxies.TargetConfig{})
+
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--pull", "never",
+		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=svc", "-l", "docker-mcp-transport=stdio",
+		"-e", "HTTPS_PROXY", "-e", "NO_PROXY",
+		"--dns", "10.0.0.2", "-l", "site=paris",
+	}, args)
+	assert.Equal(t, []string{"HTTPS_PROXY=http://proxy:3128", "NO_PROXY=localhost"}, env)
+}
+
+func TestValidateExtraRunArgs(t *testing.T) {
+	require.NoError(t, validateExtraRunArgs([]string{"--dns", "10.0.0.2", "--add-host=db:10.0.0.3"}, defaultRunPolicy()))
+
+	for _, arg := range []string{"--privileged", "--cap-add=SYS_ADMIN", "--pid", "-v"} {
+		err := validateExtraRunArgs([]string{"--dns", "10.0.0.2", arg}, defaultRunPolicy())
+		require.Error(t, err, arg)
+		assert.Contains(t, err.Error(), arg)
+	}
+
+	// Attached values, grouped short flags and flags that are not known to be safe
+	for _, args := range [][]string{
+		{"-v/var/run/docker.sock:/s"},
+		{"-v=/var/run/docker.sock:/s"},
+		{"-itv/:/host"},
+		{"-u", "0"},
+		{"-u0"},
+		{"--user=0"},
+		{"--group-add", "docker"},
+		{"--sysctl", "net.ipv4.ip_forward=1"},
+		{"--runtime=runc"},
+		{"--network=container:db"},
+		{"--net", "host"},
+		{"--dns", "10.0.0.2", "alpine"},
+	} {
+		require.Error(t, validateExtraRunArgs(args, defaultRunPolicy()), args)
+	}
+
+	require.NoError(t, validateExtraRunArgs([]string{"--privileged"}, nil))
+}
+
+func TestApplyExtraVolumes(t *testing.T) {
+	clientPool := &clientPool{}
+
+	args, _ := clientPool.argsAndEnv(&catalog.ServerConfig{
+		Name: "fs",
+		ExtraVolumes: []catalog.VolumeMount{
+			{HostPath: "/projects/app", ContainerPath: "/workspace"},
+			{HostPath: "/projects/docs", ContainerPath: "/docs", ReadOnly: true},
+		},
+	}, nil, proxies.TargetConfig{})
+
+	assert.Equal(t, []string{
+		"run", "--rm", "-i", "--init", "--security-opt", "no-new-privileges", "--pull", "never",
+		"-l", "docker-mcp=true", "-l", "docker-mcp-tool-type=mcp", "-l", "docker-mcp-name=fs", "-l", "docker-mcp-transport=stdio",
+		"-v", "/projects/app:/workspace", "-v", "/projects/docs:/docs:ro",
+	}, args)
+}
+
+func TestApplyRegistryNetwork(t *testing.T) {
+	clientPool := &clientPool{networks: []string{"gateway-network"}}
+
+	args, _ := clientPool.argsAndEnv(&catalog.ServerConfig{Name: "svc", Network: "sidecar-db"}, nil, proxies.TargetConfig{})
+	assert.Contains(t, args, "sidecar-db")
+	assert.NotContains(t, args, "gateway-network")
+
+	args, _ = clientPool.argsAndEnv(&catalog.ServerConfig{Name: "svc"}, nil, proxies.TargetConfig{})
+	assert.Contains(t, args, "gateway-network")
+
+	args, _ = clientPool.argsAndEnv(&catalog.ServerConfig{Name: "svc", Network: "sidecar-db", Spec: catalog.Server{DisableNetwork: true}}, nil, proxies.TargetConfig{})
+	assert.Contains(t, args, "none")
+	assert.NotContains(t, args, "sidecar-db")
+}
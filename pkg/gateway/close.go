@@ -0,0 +1,35 @@
+package gateway
+
+import (
+	"errors"
+	"slices"
+)
+
+// onClose registers a function called by Close, in the reverse order of registration.
+func (g *Gateway) onClose(closer func() error) {
+	g.closeMu.Lock()
+	defer g.closeMu.Unlock()
+
+	g.closers = append(g.closers, closer)
+}
+
+// Close tears down everything the gateway started: the transport server, the configuration
+// watchers and the MCP servers' containers. Run returns once the gateway is closed.
+// It's safe to call Close more than once, or concurrently with Run returning.
+func (g *Gateway) Close() error {
+	g.closeMu.Lock()
+	closers := g.closers
+	g.closers = nil
+	g.closeMu.Unlock()
+
+	var errs []error
+	for _, closer := range slices.Backward(closers) {
+		if err := closer(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	g.clientPool.Close()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,37 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClose(t *testing.T) {
+	g := NewGateway(Config{}, nil)
+
+	var order []string
+	g.onClose(func() error {
+		order = append(order, "context")
+		return nil
+	})
+	g.onClose(func() error {
+		order = append(order, "watcher")
+		return errors.New("watcher failed")
+	})
+	g.onClose(func() error {
+		order = append(order, "server")
+		return errors.New("server failed")
+	})
+
+	err := g.Close()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "watcher failed")
+	assert.ErrorContains(t, err, "server failed")
+	assert.Equal(t, []string{"server", "watcher", "context"}, order)
+
+	// Closing again is a no-op
+	require.NoError(t, g.Close())
+	assert.Len(t, order, 3)
+}
@@ -1,6 +1,10 @@
 package gateway
 
-import "github.com/docker/mcp-gateway/pkg/catalog"
+import (
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
 
 type Config struct {
 	Options
@@ -38,4 +42,28 @@ type Options struct {
 	DynamicTools            bool
 	ToolNamePrefix          bool
 	LogFilePath             string
+	LogLevel                string
+	MaxConcurrentLaunches   int
+	AllowPrivilegedRunArgs  bool
+	RunPolicyPath           string
+	AllowedEnvReferences    []string
+	AllowedMountRoots       []string
+	ReadOnlyConfig          bool
+	FailFastPull            bool
+	PullTimeout             time.Duration
+	ToolCallTimeout         time.Duration
+	InternalToolTimeout     time.Duration
+	PollInterval            time.Duration
+	FindCacheTTL            time.Duration
+	ToolCacheTTL            time.Duration
+	ToolQuotas              []string
+	InternalToolsDir        string
+	FindRankField           string
+	FindRankWeight          float64
+	FindFeedbackWeight      float64
+	FindLogPath             string
+	JSONFormat              string
+	StructuredContent       bool
+	DiscoverableTools       bool
+	AlwaysOnTools           []string
 }
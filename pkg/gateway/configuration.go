@@ -1,13 +1,15 @@
 package gateway
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
@@ -30,6 +32,11 @@ type Configuration struct {
 	config      map[string]map[string]any
 	tools       config.ToolsConfig
 	secrets     map[string]string
+	registry    map[string]config.Tile       // Per-server settings from the registry files
+	envFiles    map[string]map[string]string // Per-server environment read from the registry's env files
+	envFileErrs map[string]error             // Per-server errors reading the registry's env files
+	configDefs  map[string]any               // JSON schema definitions shared by the config schemas, from the catalogs' $defs
+	allowedEnv  []string                     // Environment variables that ${env:NAME} references can resolve
 	SessionName string
 }
 
@@ -78,9 +85,13 @@ func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[str
 			Name: serverName,
 			Spec: server,
 			Config: map[string]any{
-				oci.CanonicalizeServerName(serverName): c.config[oci.CanonicalizeServerName(serverName)],
+				oci.CanonicalizeServerName(serverName): resolveEnvReferences(c.config[oci.CanonicalizeServerName(serverName)], c.allowedEnv),
 			},
-			Secrets: c.secrets, // TODO: we could keep just the secrets for this server
+			Secrets:      c.secrets, // TODO: we could keep just the secrets for this server
+			ExtraRunArgs: c.registry[serverName].ExtraRunArgs,
+			ExtraEnv:     c.extraEnv(serverName),
+			ExtraVolumes: c.registry[serverName].Volumes,
+			Network:      c.registry[serverName].Network,
 		}, nil, true
 	}
 
@@ -92,8 +103,86 @@ func (c *Configuration) Find(serverName string) (*catalog.ServerConfig, *map[str
 	return nil, &byName, true
 }
 
-// Persist writes the configuration files to the session directory if SessionName is set
-func (c *Configuration) Persist() error {
+// maxSimilarServers is how many server names FindSimilar suggests at most
+const maxSimilarServers = 3
+
+// FindSimilar returns the names of the catalog's servers closest to a name that's not found,
+// by edit distance, to suggest corrections for typos. The closest come first.
+func (c *Configuration) FindSimilar(serverName string) []string {
+	serverName = strings.ToLower(strings.TrimSpace(serverName))
+	if serverName == "" {
+		return nil
+	}
+
+	// Allow one typo per three characters, at least two
+	maxDistance := max(2, len(serverName)/3)
+
+	distances := map[string]int{}
+	for name := range c.servers {
+		lower := strings.ToLower(name)
+		distance := editDistance(serverName, lower)
+		if distance <= maxDistance || strings.HasPrefix(lower, serverName) {
+			distances[name] = distance
+		}
+	}
+
+	similar := slices.Collect(maps.Keys(distances))
+	slices.SortFunc(similar, func(a, b string) int {
+		if distances[a] != distances[b] {
+			return distances[a] - distances[b]
+		}
+		return strings.Compare(a, b)
+	})
+	if len(similar) > maxSimilarServers {
+		similar = similar[:maxSimilarServers]
+	}
+
+	return similar
+}
+
+// editDistance is the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// isEnabled tells whether a server is enabled. A bundle is enabled when all its members are.
+func (c *Configuration) isEnabled(serverName string) bool {
+	server := c.servers[serverName]
+	if !server.IsBundle() {
+		return slices.Contains(c.serverNames, serverName)
+	}
+
+	for _, member := range server.Members {
+		if !slices.Contains(c.serverNames, member) {
+			return false
+		}
+	}
+	return len(server.Members) > 0
+}
+
+// Persist writes the configuration files to the session directory if SessionName is set.
+// With a sandbox directory, the session directory is created under it instead of ~/.docker/mcp/.
+func (c *Configuration) Persist(sandboxDir string) error {
 	if c.SessionName == "" {
 		return nil // No session name set, nothing to persist
 	}
@@ -103,15 +192,15 @@ func (c *Configuration) Persist() error {
 		Servers: make(map[string]config.Tile),
 	}
 	for _, serverName := range c.serverNames {
-		registry.Servers[serverName] = config.Tile{
-			Ref: serverName,
-		}
+		tile := c.registry[serverName]
+		tile.Ref = serverName
+		registry.Servers[serverName] = tile
 	}
 	registryBytes, err := yaml.Marshal(registry)
 	if err != nil {
 		return fmt.Errorf("failed to marshal registry: %w", err)
 	}
-	if err := config.WriteConfigFileToSession(c.SessionName, "registry.yaml", registryBytes); err != nil {
+	if err := c.writeSessionFile(sandboxDir, "registry.yaml", registryBytes); err != nil {
 		return fmt.Errorf("failed to write registry.yaml: %w", err)
 	}
 
@@ -120,7 +209,7 @@ func (c *Configuration) Persist() error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
-	if err := config.WriteConfigFileToSession(c.SessionName, "config.yaml", configBytes); err != nil {
+	if err := c.writeSessionFile(sandboxDir, "config.yaml", configBytes); err != nil {
 		return fmt.Errorf("failed to write config.yaml: %w", err)
 	}
 
@@ -129,7 +218,7 @@ func (c *Configuration) Persist() error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal tools: %w", err)
 	}
-	if err := config.WriteConfigFileToSession(c.SessionName, "tools.yaml", toolsBytes); err != nil {
+	if err := c.writeSessionFile(sandboxDir, "tools.yaml", toolsBytes); err != nil {
 		return fmt.Errorf("failed to write tools.yaml: %w", err)
 	}
 
@@ -137,6 +226,13 @@ func (c *Configuration) Persist() error {
 	return nil
 }
 
+func (c *Configuration) writeSessionFile(sandboxDir, name string, content []byte) error {
+	if sandboxDir == "" {
+		return config.WriteConfigFileToSession(c.SessionName, name, content)
+	}
+	return writeInternalToolFile(sandboxDir, filepath.Join(c.SessionName, name), content, 0o644)
+}
+
 type FileBasedConfiguration struct {
 	CatalogPath        []string
 	ServerNames        []string // Takes precedence over the RegistryPath
@@ -147,13 +243,22 @@ type FileBasedConfiguration struct {
 	OciRef             []string         // OCI references to fetch server definitions from
 	MCPRegistryServers []catalog.Server // Servers fetched from MCP registries
 	Watch              bool
+	PollInterval       time.Duration // Optional, poll http(s) sources for changes when watching
 	McpOAuthDcrEnabled bool
 	sessionName        string // Session name for persisting configuration
 
 	docker docker.Client
+
+	// Content read from stdin or fetched from a URL, read only once.
+	remoteMu sync.Mutex
+	remote   map[string][]byte
 }
 
 func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan Configuration, func() error, error) {
+	if err := validateStdinPaths(c.remotePaths()); err != nil {
+		return Configuration{}, nil, nil, err
+	}
+
 	configuration, err := c.readOnce(ctx)
 	if err != nil {
 		return Configuration{}, nil, nil, err
@@ -162,10 +267,14 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 		return configuration, nil, func() error { return nil }, nil
 	}
 
+	for _, path := range c.remotePaths() {
+		log.Log("  - Not watching", path, "(only local files can be watched)")
+	}
+
 	var registryPaths []string
 	if len(c.ServerNames) == 0 {
 		for _, path := range c.RegistryPath {
-			if path != "" {
+			if path != "" && !config.IsRemote(path) {
 				registryPath, err := config.FilePath(path)
 				if err != nil {
 					return Configuration{}, nil, nil, err
@@ -177,7 +286,7 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 
 	var configPaths []string
 	for _, path := range c.ConfigPath {
-		if path != "" {
+		if path != "" && !config.IsRemote(path) {
 			configPath, err := config.FilePath(path)
 			if err != nil {
 				return Configuration{}, nil, nil, err
@@ -188,7 +297,7 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 
 	var toolsPaths []string
 	for _, path := range c.ToolsPath {
-		if path != "" {
+		if path != "" && !config.IsRemote(path) {
 			toolsPath, err := config.FilePath(path)
 			if err != nil {
 				return Configuration{}, nil, nil, err
@@ -202,8 +311,24 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 		return Configuration{}, nil, nil, err
 	}
 
+	// Optionally poll http(s) sources, since they can't be watched.
+	var poller *urlPoller
+	var pollTicker *time.Ticker
+	var pollTicks <-chan time.Time
+	if urls := c.urlPaths(); c.PollInterval > 0 && len(urls) > 0 {
+		log.Log("  - Polling", urls, "every", c.PollInterval)
+		poller = newURLPoller(urls)
+		poller.poll(ctx)
+		pollTicker = time.NewTicker(c.PollInterval)
+		pollTicks = pollTicker.C
+	}
+
 	updates := make(chan Configuration)
 	go func() {
+		if pollTicker != nil {
+			defer pollTicker.Stop()
+		}
+
 		for {
 			select {
 			case _, ok := <-watcher.Events:
@@ -221,17 +346,24 @@ func (c *FileBasedConfiguration) Read(ctx context.Context) (Configuration, chan
 					}
 				}
 
-				configuration, err := c.readOnce(ctx)
-				if err != nil {
-					log.Log("Error reading configuration:", err)
+			case <-pollTicks:
+				changed := poller.poll(ctx)
+				if len(changed) == 0 {
 					continue
 				}
-
-				updates <- configuration
+				c.updateRemote(changed)
 
 			case <-ctx.Done():
 				return
 			}
+
+			configuration, err := c.readOnce(ctx)
+			if err != nil {
+				log.Log("Error reading configuration:", err)
+				continue
+			}
+
+			updates <- configuration
 		}
 	}()
 
@@ -264,6 +396,7 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 	log.Log("- Reading configuration...")
 
 	var serverNames []string
+	var registry map[string]config.Tile
 	if len(c.ServerNames) > 0 {
 		serverNames = c.ServerNames
 	} else {
@@ -273,6 +406,7 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		}
 
 		serverNames = registryConfig.ServerNames()
+		registry = registryConfig.Servers
 	}
 
 	// check for docker.io self-contained servers
@@ -396,6 +530,10 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		}
 	}
 
+	secrets = matchSecretNames(secrets, servers)
+
+	envFiles, envFileErrs := readEnvFiles(ctx, registry, c.lookupSecret(secrets))
+
 	log.Log("- Configuration read in", time.Since(start))
 	return Configuration{
 		serverNames: serverNames,
@@ -403,12 +541,121 @@ func (c *FileBasedConfiguration) readOnce(ctx context.Context) (Configuration, e
 		config:      serversConfig,
 		tools:       serverToolsConfig,
 		secrets:     secrets,
+		registry:    registry,
+		envFiles:    envFiles,
+		envFileErrs: envFileErrs,
+		configDefs:  mcpCatalog.Defs,
 	}, nil
 }
 
 func (c *FileBasedConfiguration) readCatalog(ctx context.Context) (catalog.Catalog, error) {
 	log.Log("  - Reading catalog from", c.CatalogPath)
-	return catalog.ReadFrom(ctx, c.CatalogPath)
+	if !slices.Contains(c.CatalogPath, config.StdinPath) {
+		return catalog.ReadFrom(ctx, c.CatalogPath)
+	}
+
+	merged := catalog.Catalog{
+		Servers: map[string]catalog.Server{},
+	}
+	for _, catalogPath := range c.CatalogPath {
+		var mcpCatalog catalog.Catalog
+		if catalogPath == config.StdinPath {
+			buf, err := c.readConfigFile(ctx, catalogPath)
+			if err != nil {
+				return catalog.Catalog{}, fmt.Errorf("reading catalog from stdin: %w", err)
+			}
+			if mcpCatalog, err = catalog.Parse(buf); err != nil {
+				return catalog.Catalog{}, fmt.Errorf("parsing catalog from stdin: %w", err)
+			}
+		} else {
+			var err error
+			if mcpCatalog, err = catalog.ReadFrom(ctx, []string{catalogPath}); err != nil {
+				return catalog.Catalog{}, err
+			}
+		}
+
+		merged.Merge(mcpCatalog, catalogPath)
+	}
+
+	return merged, nil
+}
+
+// readConfigFile reads a registry, config or tools file. Content read from stdin
+// or fetched from a URL is read only once and cached since it can't be watched.
+func (c *FileBasedConfiguration) readConfigFile(ctx context.Context, path string) ([]byte, error) {
+	if !config.IsRemote(path) {
+		return config.ReadConfigFile(ctx, c.docker, path)
+	}
+
+	c.remoteMu.Lock()
+	defer c.remoteMu.Unlock()
+
+	if buf, found := c.remote[path]; found {
+		return buf, nil
+	}
+
+	buf, err := config.ReadRemote(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.remote == nil {
+		c.remote = map[string][]byte{}
+	}
+	c.remote[path] = buf
+
+	return buf, nil
+}
+
+// updateRemote replaces the cached content of the sources that changed remotely.
+func (c *FileBasedConfiguration) updateRemote(changed map[string][]byte) {
+	c.remoteMu.Lock()
+	defer c.remoteMu.Unlock()
+
+	for path, buf := range changed {
+		if _, found := c.remote[path]; found {
+			c.remote[path] = buf
+		}
+	}
+}
+
+// urlPaths returns the configuration paths that are fetched from an http(s) URL.
+func (c *FileBasedConfiguration) urlPaths() []string {
+	var paths []string
+	for _, path := range slices.Concat(c.CatalogPath, c.RegistryPath, c.ConfigPath, c.ToolsPath) {
+		if config.IsRemote(path) && path != config.StdinPath && !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// remotePaths returns the configuration paths that are read from stdin or fetched from a URL.
+func (c *FileBasedConfiguration) remotePaths() []string {
+	var paths []string
+	if slices.Contains(c.CatalogPath, config.StdinPath) {
+		paths = append(paths, config.StdinPath)
+	}
+	for _, path := range slices.Concat(c.RegistryPath, c.ConfigPath, c.ToolsPath) {
+		if config.IsRemote(path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// validateStdinPaths makes sure stdin is used for at most one configuration path.
+func validateStdinPaths(paths []string) error {
+	count := 0
+	for _, path := range paths {
+		if path == config.StdinPath {
+			count++
+		}
+	}
+	if count > 1 {
+		return fmt.Errorf("stdin (%q) can only be used for one configuration path, got %d", config.StdinPath, count)
+	}
+	return nil
 }
 
 func (c *FileBasedConfiguration) readRegistry(ctx context.Context) (config.Registry, error) {
@@ -426,7 +673,7 @@ func (c *FileBasedConfiguration) readRegistry(ctx context.Context) (config.Regis
 		}
 
 		log.Log("  - Reading registry from", registryPath)
-		yaml, err := config.ReadConfigFile(ctx, c.docker, registryPath)
+		yaml, err := c.readConfigFile(ctx, registryPath)
 		if err != nil {
 			return config.Registry{}, fmt.Errorf("reading registry file %s: %w", registryPath, err)
 		}
@@ -461,7 +708,7 @@ func (c *FileBasedConfiguration) readConfig(ctx context.Context) (map[string]map
 		}
 
 		log.Log("  - Reading config from", configPath)
-		yaml, err := config.ReadConfigFile(ctx, c.docker, configPath)
+		yaml, err := c.readConfigFile(ctx, configPath)
 		if err != nil {
 			return nil, fmt.Errorf("reading config file %s: %w", configPath, err)
 		}
@@ -498,7 +745,7 @@ func (c *FileBasedConfiguration) readToolsConfig(ctx context.Context) (config.To
 		}
 
 		log.Log("  - Reading tools from", toolsPath)
-		yaml, err := config.ReadConfigFile(ctx, c.docker, toolsPath)
+		yaml, err := c.readConfigFile(ctx, toolsPath)
 		if err != nil {
 			return config.ToolsConfig{}, fmt.Errorf("reading tools file %s: %w", toolsPath, err)
 		}
@@ -556,9 +803,36 @@ func (c *FileBasedConfiguration) readDockerDesktopSecrets(ctx context.Context, s
 	return secretsByName, nil
 }
 
-func (c *FileBasedConfiguration) readSecretsFromFile(ctx context.Context, path string) (map[string]string, error) {
-	secrets := map[string]string{}
+// lookupSecret looks a secret up in the secrets already read, then in the secrets providers:
+// with docker-desktop, only the secrets of the enabled servers are read upfront.
+func (c *FileBasedConfiguration) lookupSecret(secrets map[string]string) secretLookup {
+	return func(ctx context.Context, name string) (string, bool) {
+		if value, found := secrets[name]; found {
+			return value, true
+		}
+
+		for secretPath := range strings.SplitSeq(c.SecretsPath, ":") {
+			var values map[string]string
+			var err error
+			if secretPath == "docker-desktop" {
+				values, err = c.docker.ReadSecrets(ctx, []string{name}, true)
+			} else {
+				values, err = c.readSecretsFromFile(ctx, secretPath)
+			}
+			if err != nil {
+				continue
+			}
+
+			if value, found := values[name]; found {
+				return value, true
+			}
+		}
 
+		return "", false
+	}
+}
+
+func (c *FileBasedConfiguration) readSecretsFromFile(ctx context.Context, path string) (map[string]string, error) {
 	// Resolve relative paths to ~/.docker/mcp/
 	resolvedPath, err := config.FilePath(path)
 	if err != nil {
@@ -570,32 +844,112 @@ func (c *FileBasedConfiguration) readSecretsFromFile(ctx context.Context, path s
 		return nil, fmt.Errorf("reading secrets from %s: %w", path, err)
 	}
 
-	scanner := bufio.NewScanner(bytes.NewReader(buf))
-	for scanner.Scan() {
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
-		}
+	secrets, err := parseDotEnv(ctx, buf)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets file %s: %w", path, err)
+	}
 
-		line := scanner.Text()
-		if strings.HasPrefix(line, "#") {
-			continue
+	return secrets, nil
+}
+
+// currentConfiguration returns the gateway's configuration, with the servers enabled by mcp-add
+// and mcp-remove since it was read.
+func (g *Gateway) currentConfiguration() Configuration {
+	g.configurationMu.RLock()
+	defer g.configurationMu.RUnlock()
+
+	return g.configuration
+}
+
+func (g *Gateway) setConfiguration(configuration Configuration) {
+	g.configurationMu.Lock()
+	defer g.configurationMu.Unlock()
+
+	g.configuration = configuration
+}
+
+// updateConfiguration changes the configuration under the lock. The snapshots returned by
+// currentConfiguration share its maps, so update must replace a map rather than modify it.
+func (g *Gateway) updateConfiguration(update func(configuration *Configuration)) {
+	g.configurationMu.Lock()
+	defer g.configurationMu.Unlock()
+
+	update(&g.configuration)
+}
+
+// setServerNames changes the enabled servers. The cached mcp-find responses are stale after that.
+func (g *Gateway) setServerNames(serverNames []string) {
+	g.updateConfiguration(func(configuration *Configuration) {
+		configuration.serverNames = serverNames
+	})
+
+	g.clearFindCache()
+}
+
+// setSecret sets the value of a secret in memory.
+func (g *Gateway) setSecret(name, value string) {
+	g.updateConfiguration(func(configuration *Configuration) {
+		secrets := maps.Clone(configuration.secrets)
+		if secrets == nil {
+			secrets = map[string]string{}
 		}
+		secrets[name] = value
+		configuration.secrets = secrets
+	})
+}
 
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
+// setConfigValue sets a config value of a server in memory and returns the previous one.
+func (g *Gateway) setConfigValue(serverName, key string, value any) any {
+	var oldValue any
+	g.updateConfiguration(func(configuration *Configuration) {
+		serverConfig := maps.Clone(configuration.config[serverName])
+		if serverConfig == nil {
+			serverConfig = map[string]any{}
 		}
+		oldValue = serverConfig[key]
+		serverConfig[key] = value
 
-		var key, value string
-		key, value, ok := strings.Cut(line, "=")
-		if !ok {
-			return nil, fmt.Errorf("invalid line in secrets file: %s", line)
+		config := maps.Clone(configuration.config)
+		if config == nil {
+			config = map[string]map[string]any{}
 		}
+		config[serverName] = serverConfig
+		configuration.config = config
+	})
 
-		secrets[key] = value
+	return oldValue
+}
+
+// persistConfiguration writes the current configuration to the session, if any.
+func (g *Gateway) persistConfiguration() {
+	configuration := g.currentConfiguration()
+	if err := configuration.Persist(g.InternalToolsDir); err != nil {
+		log.Log("Warning: Failed to persist configuration:", err)
 	}
+}
 
-	return secrets, nil
+// matchSecretNames makes the secrets of the servers available under the name the catalog uses,
+// when the secrets files are case-insensitive and store them under another case.
+func matchSecretNames(secrets map[string]string, servers map[string]catalog.Server) map[string]string {
+	if os.Getenv(config.SecretsCaseInsensitiveEnv) != "1" || len(secrets) == 0 {
+		return secrets
+	}
+
+	for _, server := range servers {
+		for _, secret := range server.Secrets {
+			if _, found := secrets[secret.Name]; found {
+				continue
+			}
+			for name, value := range secrets {
+				if strings.EqualFold(name, secret.Name) {
+					secrets[secret.Name] = value
+					break
+				}
+			}
+		}
+	}
+
+	return secrets
 }
 
 // readServersFromOci fetches and parses server definitions from OCI references
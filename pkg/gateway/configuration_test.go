@@ -2,6 +2,8 @@ package gateway
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -133,3 +135,127 @@ func TestReadServersFromOciNoRefs(t *testing.T) {
 	require.NoError(t, err)
 	assert.Empty(t, servers, "Should return empty map when no OCI references provided")
 }
+
+func TestReadConfigFileFromURLIsCached(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("github:\n  ref: \"\"\n"))
+	}))
+	defer server.Close()
+
+	configuration := &FileBasedConfiguration{}
+
+	for range 2 {
+		buf, err := configuration.readConfigFile(context.Background(), server.URL)
+		require.NoError(t, err)
+		assert.Equal(t, "github:\n  ref: \"\"\n", string(buf))
+	}
+	assert.Equal(t, 1, requests)
+}
+
+func TestReadConfigFileFromURLError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	configuration := &FileBasedConfiguration{}
+
+	_, err := configuration.readConfigFile(context.Background(), server.URL)
+	require.ErrorContains(t, err, "404")
+}
+
+func TestValidateStdinPaths(t *testing.T) {
+	require.NoError(t, validateStdinPaths(nil))
+	require.NoError(t, validateStdinPaths([]string{"-", "https://example.com/config.yaml"}))
+	require.Error(t, validateStdinPaths([]string{"-", "-"}))
+}
+
+func TestFindSimilar(t *testing.T) {
+	configuration := Configuration{
+		servers: map[string]catalog.Server{
+			"github":             {},
+			"github-official":    {},
+			"gitlab":             {},
+			"duckduckgo":         {},
+			"fetch":              {},
+			"filesystem":         {},
+			"atlassian":          {},
+			"brave":              {},
+			"context7":           {},
+			"sequentialthinking": {},
+		},
+	}
+
+	assert.Equal(t, []string{"github", "gitlab"}, configuration.FindSimilar("githb"))
+	assert.Equal(t, []string{"github", "gitlab", "github-official"}, configuration.FindSimilar("git"))
+	assert.Equal(t, []string{"duckduckgo"}, configuration.FindSimilar("DuckDuckGo "))
+	assert.Equal(t, []string{"filesystem"}, configuration.FindSimilar("filesytem"))
+	assert.Equal(t, []string{"fetch"}, configuration.FindSimilar("fecth"))
+	assert.Empty(t, configuration.FindSimilar("kubernetes"))
+	assert.Empty(t, configuration.FindSimilar(""))
+}
+
+func TestQuoteServerNames(t *testing.T) {
+	assert.Equal(t, "'github'", quoteServerNames([]string{"github"}))
+	assert.Equal(t, "'github', 'gitlab' or 'gitea'", quoteServerNames([]string{"github", "gitlab", "gitea"}))
+}
+
+func TestIsEnabled(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"postgres", "redis"},
+		servers: map[string]catalog.Server{
+			"postgres":   {Image: "mcp/postgres"},
+			"redis":      {Image: "mcp/redis"},
+			"proxy":      {Image: "mcp/proxy"},
+			"cache":      {Type: "bundle", Members: []string{"postgres", "redis"}},
+			"data-stack": {Type: "bundle", Members: []string{"postgres", "redis", "proxy"}},
+			"empty":      {Type: "bundle"},
+		},
+	}
+
+	assert.True(t, configuration.isEnabled("postgres"))
+	assert.False(t, configuration.isEnabled("proxy"))
+	assert.True(t, configuration.isEnabled("cache"))
+	assert.False(t, configuration.isEnabled("data-stack"))
+	assert.False(t, configuration.isEnabled("empty"))
+}
+
+func TestMatchSecretNames(t *testing.T) {
+	servers := map[string]catalog.Server{
+		"github": {Secrets: []catalog.Secret{{Name: "github.personal_access_token", Env: "GITHUB_TOKEN"}}},
+	}
+	read := func() map[string]string {
+		return map[string]string{"GitHub.Personal_Access_Token": "ghp_123"}
+	}
+
+	// Names are matched exactly by default
+	assert.NotContains(t, matchSecretNames(read(), servers), "github.personal_access_token")
+
+	t.Setenv("DOCKER_MCP_SECRETS_CASE_INSENSITIVE", "1")
+	secrets := matchSecretNames(read(), servers)
+	assert.Equal(t, "ghp_123", secrets["github.personal_access_token"])
+	assert.Empty(t, missingSecrets(Configuration{serverNames: []string{"github"}, servers: servers, secrets: secrets}))
+}
+
+func TestConfigurationUpdatesDontChangeSnapshots(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			config:  map[string]map[string]any{"github": {"owner": "docker"}},
+			secrets: map[string]string{"github.token": "old"},
+		},
+	}
+	snapshot := g.currentConfiguration()
+
+	assert.Equal(t, "docker", g.setConfigValue("github", "owner", "moby"))
+	g.setConfigValue("slack", "channel", "general")
+	g.setSecret("github.token", "new")
+
+	assert.Equal(t, map[string]map[string]any{"github": {"owner": "docker"}}, snapshot.config)
+	assert.Equal(t, map[string]string{"github.token": "old"}, snapshot.secrets)
+
+	current := g.currentConfiguration()
+	assert.Equal(t, map[string]map[string]any{"github": {"owner": "moby"}, "slack": {"channel": "general"}}, current.config)
+	assert.Equal(t, map[string]string{"github.token": "new"}, current.secrets)
+}
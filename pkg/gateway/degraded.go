@@ -0,0 +1,107 @@
+package gateway
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// serverFailure is why an enabled server doesn't contribute its capabilities
+type serverFailure struct {
+	Server string    `json:"server"`
+	Error  string    `json:"error"`
+	Since  time.Time `json:"since"`
+}
+
+// degradedServers tracks the enabled servers that failed to start or to list their tools.
+// The zero value is ready to use.
+type degradedServers struct {
+	mu       sync.Mutex
+	failures map[string]serverFailure
+}
+
+func (d *degradedServers) fail(serverName string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.failures == nil {
+		d.failures = map[string]serverFailure{}
+	}
+
+	since := time.Now()
+	if previous, found := d.failures[serverName]; found {
+		since = previous.Since
+	}
+	d.failures[serverName] = serverFailure{
+		Server: serverName,
+		Error:  err.Error(),
+		Since:  since,
+	}
+}
+
+func (d *degradedServers) recover(serverName string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.failures, serverName)
+}
+
+// retain forgets about the servers that are no longer enabled.
+func (d *degradedServers) retain(serverNames []string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for serverName := range d.failures {
+		if !slices.Contains(serverNames, serverName) {
+			delete(d.failures, serverName)
+		}
+	}
+}
+
+func (d *degradedServers) has(serverName string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	_, found := d.failures[serverName]
+	return found
+}
+
+func (d *degradedServers) list() []serverFailure {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	failures := []serverFailure{}
+	for _, failure := range d.failures {
+		failures = append(failures, failure)
+	}
+	sort.Slice(failures, func(i, j int) bool {
+		return failures[i].Server < failures[j].Server
+	})
+
+	return failures
+}
+
+// createDegradedServersTool implements a tool listing the servers whose capabilities are missing
+func (g *Gateway) createDegradedServersTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "degraded-servers",
+		Description: "List the enabled MCP servers that failed to start or to list their tools, with the error and since when. Their tools are not available until the next successful reload.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+		},
+	}
+
+	handler := func(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return g.jsonToolResult(g.degraded.list(), true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("degraded-servers", handler),
+	}
+}
@@ -0,0 +1,34 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDegradedServers(t *testing.T) {
+	var degraded degradedServers
+	assert.Empty(t, degraded.list())
+
+	degraded.fail("github", errors.New("can't start: boom"))
+	degraded.fail("fetch", errors.New("can't list tools: timeout"))
+
+	failures := degraded.list()
+	require.Len(t, failures, 2)
+	assert.Equal(t, "fetch", failures[0].Server)
+	assert.Equal(t, "github", failures[1].Server)
+	assert.Equal(t, "can't start: boom", failures[1].Error)
+
+	// The first failure time is kept
+	since := failures[1].Since
+	degraded.fail("github", errors.New("can't start: again"))
+	assert.Equal(t, since, degraded.list()[1].Since)
+
+	degraded.recover("fetch")
+	assert.Len(t, degraded.list(), 1)
+
+	degraded.retain([]string{"fetch"})
+	assert.Empty(t, degraded.list())
+}
@@ -0,0 +1,81 @@
+package gateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+// startupOrder groups the enabled servers in waves: the servers of a wave only depend,
+// through the registry's dependsOn, on servers of the previous waves. Servers keep their
+// relative order within a wave. Dependencies on servers that are not enabled are ignored
+// here, see unavailableDependency.
+func startupOrder(serverNames []string, registry map[string]config.Tile) ([][]string, error) {
+	started := map[string]bool{}
+	remaining := slices.Clone(serverNames)
+
+	var waves [][]string
+	for len(remaining) > 0 {
+		var wave, next []string
+		for _, serverName := range remaining {
+			if dependenciesStarted(serverName, serverNames, registry, started) {
+				wave = append(wave, serverName)
+			} else {
+				next = append(next, serverName)
+			}
+		}
+
+		if len(wave) == 0 {
+			return nil, fmt.Errorf("circular dependency between servers: %s", strings.Join(findDependencyCycle(next, registry), " -> "))
+		}
+
+		for _, serverName := range wave {
+			started[serverName] = true
+		}
+		waves = append(waves, wave)
+		remaining = next
+	}
+
+	return waves, nil
+}
+
+func dependenciesStarted(serverName string, serverNames []string, registry map[string]config.Tile, started map[string]bool) bool {
+	for _, dependency := range registry[serverName].DependsOn {
+		if slices.Contains(serverNames, dependency) && !started[dependency] {
+			return false
+		}
+	}
+	return true
+}
+
+// findDependencyCycle follows the dependencies between servers that could not be started,
+// until one is seen twice. Each of them depends on another one, so there's a cycle.
+func findDependencyCycle(serverNames []string, registry map[string]config.Tile) []string {
+	var path []string
+	for serverName := serverNames[0]; ; {
+		if i := slices.Index(path, serverName); i != -1 {
+			return append(path[i:], serverName)
+		}
+		path = append(path, serverName)
+
+		for _, dependency := range registry[serverName].DependsOn {
+			if slices.Contains(serverNames, dependency) {
+				serverName = dependency
+				break
+			}
+		}
+	}
+}
+
+// unavailableDependency returns the first dependency of a server that's not enabled or that failed to start.
+func (g *Gateway) unavailableDependency(serverName string) (string, bool) {
+	configuration := g.currentConfiguration()
+	for _, dependency := range configuration.registry[serverName].DependsOn {
+		if !slices.Contains(configuration.serverNames, dependency) || g.degraded.has(dependency) {
+			return dependency, true
+		}
+	}
+	return "", false
+}
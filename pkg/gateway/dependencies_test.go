@@ -0,0 +1,66 @@
+package gateway
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+func TestStartupOrder(t *testing.T) {
+	registry := map[string]config.Tile{
+		"proxy":  {Ref: "proxy", DependsOn: []string{"db", "cache"}},
+		"cache":  {Ref: "cache", DependsOn: []string{"db"}},
+		"report": {Ref: "report", DependsOn: []string{"proxy", "unknown"}},
+	}
+
+	waves, err := startupOrder([]string{"report", "proxy", "cache", "fetch", "db"}, registry)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"fetch", "db"}, {"cache"}, {"proxy"}, {"report"}}, waves)
+
+	// Without dependencies, all the servers start together
+	waves, err = startupOrder([]string{"fetch", "github"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"fetch", "github"}}, waves)
+}
+
+func TestStartupOrderCycle(t *testing.T) {
+	registry := map[string]config.Tile{
+		"a": {Ref: "a", DependsOn: []string{"b"}},
+		"b": {Ref: "b", DependsOn: []string{"c"}},
+		"c": {Ref: "c", DependsOn: []string{"b"}},
+	}
+
+	_, err := startupOrder([]string{"a", "b", "c", "fetch"}, registry)
+	require.EqualError(t, err, "circular dependency between servers: b -> c -> b")
+
+	_, err = startupOrder([]string{"a"}, map[string]config.Tile{"a": {Ref: "a", DependsOn: []string{"a"}}})
+	require.EqualError(t, err, "circular dependency between servers: a -> a")
+}
+
+func TestUnavailableDependency(t *testing.T) {
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"db", "proxy", "report"},
+			registry: map[string]config.Tile{
+				"proxy":  {Ref: "proxy", DependsOn: []string{"db"}},
+				"report": {Ref: "report", DependsOn: []string{"unknown"}},
+			},
+		},
+	}
+
+	_, unavailable := g.unavailableDependency("proxy")
+	assert.False(t, unavailable)
+
+	dependency, unavailable := g.unavailableDependency("report")
+	assert.True(t, unavailable)
+	assert.Equal(t, "unknown", dependency)
+
+	g.degraded.fail("db", errors.New("can't start"))
+	dependency, unavailable = g.unavailableDependency("proxy")
+	assert.True(t, unavailable)
+	assert.Equal(t, "db", dependency)
+}
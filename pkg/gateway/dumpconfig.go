@@ -0,0 +1,292 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/oci"
+)
+
+// EffectiveServerConfig is the merged configuration a server runs with.
+// Secrets are only listed by name, their values are never included.
+type EffectiveServerConfig struct {
+	Name         string                `json:"name"`
+	Enabled      bool                  `json:"enabled"`
+	Type         string                `json:"type,omitempty"`
+	Image        string                `json:"image,omitempty"`
+	RemoteURL    string                `json:"remote_url,omitempty"`
+	ToolPrefix   string                `json:"tool_prefix,omitempty"`
+	Config       map[string]any        `json:"config,omitempty"`
+	Secrets      []EffectiveSecret     `json:"secrets,omitempty"`
+	Env          []catalog.Env         `json:"env,omitempty"`
+	Tools        []string              `json:"tools,omitempty"`
+	AllowTools   []string              `json:"allow_tools,omitempty"`
+	DenyTools    []string              `json:"deny_tools,omitempty"`
+	AlwaysOn     []string              `json:"always_on_tools,omitempty"`
+	DependsOn    []string              `json:"depends_on,omitempty"`
+	ExtraRunArgs []string              `json:"extra_run_args,omitempty"`
+	ExtraEnv     []string              `json:"extra_env,omitempty"`
+	EnvFile      string                `json:"env_file,omitempty"`
+	Volumes      []catalog.VolumeMount `json:"volumes,omitempty"`
+	Network      string                `json:"network,omitempty"`
+}
+
+// EffectiveSecret is a secret a server needs, and whether it's set.
+type EffectiveSecret struct {
+	Name string `json:"name"`
+	Env  string `json:"env,omitempty"`
+	Set  bool   `json:"set"`
+}
+
+// effectiveConfig merges the catalog, the registry, the config and the tools files for a server.
+// Config keys that are not set take the default declared by the server's config schema.
+// The ${env:NAME} references are kept as is, their values are never shown.
+func (g *Gateway) effectiveConfig(configuration Configuration, serverName string) (EffectiveServerConfig, bool) {
+	server, found := configuration.servers[serverName]
+	if !found {
+		return EffectiveServerConfig{}, false
+	}
+
+	tile := configuration.registry[serverName]
+	effective := EffectiveServerConfig{
+		Name:         serverName,
+		Enabled:      slices.Contains(configuration.serverNames, serverName),
+		Type:         server.Type,
+		Image:        server.Image,
+		RemoteURL:    server.Remote.URL,
+		ToolPrefix:   g.getToolNamePrefix(configuration, &catalog.ServerConfig{Name: serverName, Spec: server}),
+		Env:          server.Env,
+		Tools:        configuration.tools.ServerTools[serverName],
+		AllowTools:   tile.AllowTools,
+		DenyTools:    tile.DenyTools,
+		AlwaysOn:     tile.AlwaysOnTools,
+		DependsOn:    tile.DependsOn,
+		ExtraRunArgs: redactRunArgs(tile.ExtraRunArgs),
+		EnvFile:      tile.EnvFile,
+		Volumes:      tile.Volumes,
+		Network:      tile.Network,
+	}
+	if effective.RemoteURL == "" {
+		effective.RemoteURL = server.SSEEndpoint
+	}
+
+	values := configuration.config[oci.CanonicalizeServerName(serverName)]
+	effective.Config = map[string]any{}
+	for key, value := range values {
+		effective.Config[key] = value
+	}
+	for _, configItem := range server.ConfigItems() {
+		for key, property := range configItem.Properties() {
+			if defaultValue, hasDefault := property["default"]; hasDefault {
+				if _, set := effective.Config[key]; !set {
+					effective.Config[key] = defaultValue
+				}
+			}
+		}
+	}
+
+	for _, secret := range server.Secrets {
+		effective.Secrets = append(effective.Secrets, EffectiveSecret{
+			Name: secret.Name,
+			Env:  secret.Env,
+			Set:  configuration.secrets[secret.Name] != "",
+		})
+	}
+
+	// Only the names of the extra env variables, they may hold credentials
+	for name := range configuration.extraEnv(serverName) {
+		effective.ExtraEnv = append(effective.ExtraEnv, name)
+	}
+	slices.Sort(effective.ExtraEnv)
+
+	return effective, true
+}
+
+// redactRunArgs hides the values of the env variables set by docker run args, they may hold credentials.
+// The flags and the names of the variables are kept: --env TOKEN=secret becomes --env TOKEN=<redacted>.
+func redactRunArgs(args []string) []string {
+	redacted := slices.Clone(args)
+
+	for i := 0; i < len(redacted); i++ {
+		arg := redacted[i]
+
+		switch {
+		case strings.HasPrefix(arg, "--") && len(arg) > 2:
+			flag, value, hasValue := strings.Cut(arg, "=")
+			flag = normalizeRunFlag(flag)
+			switch {
+			case hasValue && flag == "--env":
+				redacted[i] = arg[:len(arg)-len(value)] + redactEnvValue(value)
+			case !hasValue && !slices.Contains(booleanRunFlags, flag) && i+1 < len(redacted):
+				i++
+				if flag == "--env" {
+					redacted[i] = redactEnvValue(redacted[i])
+				}
+			}
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && arg[1] != '-':
+			// Same as parseRunArgs: the first short flag that takes a value ends the group
+			for j := 1; j < len(arg); j++ {
+				flag := normalizeRunFlag("-" + arg[j:j+1])
+				if slices.Contains(booleanRunFlags, flag) {
+					continue
+				}
+
+				value := strings.TrimPrefix(arg[j+1:], "=")
+				switch {
+				case j+1 < len(arg) && flag == "--env":
+					redacted[i] = arg[:len(arg)-len(value)] + redactEnvValue(value)
+				case j+1 == len(arg) && i+1 < len(redacted):
+					i++
+					if flag == "--env" {
+						redacted[i] = redactEnvValue(redacted[i])
+					}
+				}
+				break
+			}
+		}
+	}
+
+	return redacted
+}
+
+// redactEnvValue hides the value of a KEY=value env variable.
+// A variable without a value is passed from the environment, it's kept as is.
+func redactEnvValue(env string) string {
+	name, _, hasValue := strings.Cut(env, "=")
+	if !hasValue {
+		return env
+	}
+	return name + "=<redacted>"
+}
+
+// ConfigReport is the effective configuration of servers, plus the issues found by the static checks, if they ran.
+type ConfigReport struct {
+	Servers []EffectiveServerConfig `json:"servers"`
+	Issues  []ValidationIssue       `json:"issues,omitempty"`
+}
+
+// ShowConfig reads the configuration and returns the effective configuration of some servers,
+// or of all the enabled servers. With validate, the static checks of Validate also run.
+func (g *Gateway) ShowConfig(ctx context.Context, serverNames []string, validate bool) (*ConfigReport, error) {
+	configuration, _, stopConfigWatcher, err := g.configurator.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration: %w", err)
+	}
+	defer func() { _ = stopConfigWatcher() }()
+
+	if len(serverNames) == 0 {
+		serverNames = configuration.serverNames
+	}
+
+	report := &ConfigReport{Servers: []EffectiveServerConfig{}}
+	for _, serverName := range serverNames {
+		effective, found := g.effectiveConfig(configuration, serverName)
+		if !found {
+			if similar := configuration.FindSimilar(serverName); len(similar) > 0 {
+				return nil, fmt.Errorf("server %s not found in catalog, did you mean %s?", serverName, quoteServerNames(similar))
+			}
+			return nil, fmt.Errorf("server %s not found in catalog", serverName)
+		}
+		report.Servers = append(report.Servers, effective)
+	}
+
+	if validate {
+		report.Issues = validateConfiguration(configuration, g.ToolNamePrefix)
+	}
+
+	return report, nil
+}
+
+// createDumpConfigTool implements a tool that returns the effective configuration of one or all the enabled servers
+func (g *Gateway) createDumpConfigTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "dump-config",
+		Description: "Show the effective configuration of an MCP server, as merged from the catalog, the registry, the config and the tools files, including the runtime changes and the config defaults. Secrets are listed by name only. Without a server, all the enabled servers are returned.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"server": {
+					Type:        "string",
+					Description: "Name of the MCP server (default: all the enabled servers)",
+				},
+			},
+		},
+	}
+
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Parse parameters
+		var params struct {
+			Server string `json:"server"`
+		}
+
+		if req.Params.Arguments != nil {
+			paramsBytes, err := json.Marshal(req.Params.Arguments)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+			}
+
+			if err := json.Unmarshal(paramsBytes, &params); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments: %w", err)
+			}
+		}
+
+		// Read the current configuration, so that mcp-config-set changes are included
+		configuration := g.currentConfiguration()
+
+		serverNames := configuration.serverNames
+		if params.Server != "" {
+			serverNames = []string{params.Server}
+		}
+
+		effective := []EffectiveServerConfig{}
+		for _, serverName := range serverNames {
+			serverConfig, found := g.effectiveConfig(configuration, serverName)
+			if !found {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Error: Server '%s' not found in catalog. Use mcp-find to search for available servers.", serverName)}},
+				}, nil
+			}
+			effective = append(effective, serverConfig)
+		}
+
+		return g.jsonToolResult(effective, true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("dump-config", handler),
+	}
+}
+
+// MissingSecrets reads the configuration and returns, for each enabled server, the secrets
+// it requires that are not set. Servers that have all their secrets are left out.
+func (g *Gateway) MissingSecrets(ctx context.Context) (map[string][]string, error) {
+	configuration, _, stopConfigWatcher, err := g.configurator.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("reading configuration: %w", err)
+	}
+	defer func() { _ = stopConfigWatcher() }()
+
+	return missingSecrets(configuration), nil
+}
+
+func missingSecrets(configuration Configuration) map[string][]string {
+	missing := map[string][]string{}
+	for _, serverName := range configuration.serverNames {
+		for _, secret := range configuration.servers[serverName].Secrets {
+			if configuration.secrets[secret.Name] == "" && !slices.Contains(missing[serverName], secret.Name) {
+				missing[serverName] = append(missing[serverName], secret.Name)
+			}
+		}
+	}
+
+	return missing
+}
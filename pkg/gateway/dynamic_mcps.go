@@ -3,13 +3,17 @@ package gateway
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -19,17 +23,16 @@ import (
 
 	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/codemode"
-	"github.com/docker/mcp-gateway/pkg/config"
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/oci"
 	"github.com/docker/mcp-gateway/pkg/telemetry"
 )
 
 // mcpFindTool implements a tool for finding MCP servers in the catalog
-func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistration {
+func (g *Gateway) createMcpFindTool() *ToolRegistration {
 	tool := &mcp.Tool{
 		Name:        "mcp-find",
-		Description: "Find MCP servers in the current catalog by name, title, or description. Returns matching servers with their details.",
+		Description: "Find MCP servers in the current catalog by name, title, or description. Returns matching servers with their details. Bundles of servers are tagged with type: bundle and list their members. Only the enabled servers are searched, unless include_disabled is set to find servers to add with mcp-add.",
 		InputSchema: &jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
@@ -41,16 +44,29 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 					Type:        "integer",
 					Description: "Maximum number of results to return (default: 10)",
 				},
+				"min_score": {
+					Type:        "number",
+					Description: "Minimum relevance score, between 0 and 1, of the returned servers (default: 0, no filtering)",
+				},
+				"include_disabled": {
+					Type:        "boolean",
+					Description: "Also search the servers of the catalog that are not enabled, tagged with enabled: false (default: false)",
+				},
 			},
 			Required: []string{"query"},
 		},
 	}
 
-	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	cache := newFindCache(g.FindCacheTTL)
+	g.findCache.Store(cache)
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Parse parameters
 		var params struct {
-			Query string `json:"query"`
-			Limit int    `json:"limit"`
+			Query           string  `json:"query"`
+			Limit           int     `json:"limit"`
+			MinScore        float64 `json:"min_score"`
+			IncludeDisabled bool    `json:"include_disabled"`
 		}
 
 		if req.Params.Arguments == nil {
@@ -74,75 +90,32 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 			params.Limit = 10
 		}
 
-		// Search through the catalog servers
-		query := strings.ToLower(strings.TrimSpace(params.Query))
-		var matches []ServerMatch
+		if params.MinScore < 0 || params.MinScore > 1 {
+			return nil, fmt.Errorf("min_score must be between 0 and 1")
+		}
 
-		for serverName, server := range configuration.servers {
-			match := false
-			score := 0
-
-			// Check server name (exact match gets higher score)
-			serverNameLower := strings.ToLower(serverName)
-			if serverNameLower == query {
-				match = true
-				score = 100
-			} else if strings.Contains(serverNameLower, query) {
-				match = true
-				score = 50
-			}
+		start := time.Now()
+		defer func() { g.stats.recordSearch(time.Since(start)) }()
 
-			// Check server title
-			if server.Title != "" {
-				titleLower := strings.ToLower(server.Title)
-				if titleLower == query {
-					match = true
-					score = maxInt(score, 97)
-				} else if strings.Contains(titleLower, query) {
-					match = true
-					score = maxInt(score, 47)
-				}
-			}
+		// The servers enabled with mcp-add or mcp-remove since the tool was created count
+		configuration := g.currentConfiguration()
 
-			// Check server description
-			if server.Description != "" {
-				descriptionLower := strings.ToLower(server.Description)
-				if descriptionLower == query {
-					match = true
-					score = maxInt(score, 95)
-				} else if strings.Contains(descriptionLower, query) {
-					match = true
-					score = maxInt(score, 45)
-				}
-			}
+		// A cached search is logged too, with the servers it returned
+		cacheKey := findCacheKey(params.Query, params.Limit, params.MinScore, params.IncludeDisabled)
+		if response, matches, found := cache.get(cacheKey); found {
+			g.findLog.record(params.Query, params.Limit, params.MinScore, matches, configuration.secrets)
+			return g.jsonResult([]byte(response)), nil
+		}
 
-			// Check if it has tools that might match
-			for _, tool := range server.Tools {
-				toolNameLower := strings.ToLower(tool.Name)
-				toolDescLower := strings.ToLower(tool.Description)
-
-				if toolNameLower == query {
-					match = true
-					score = maxInt(score, 90)
-				} else if strings.Contains(toolNameLower, query) {
-					match = true
-					score = maxInt(score, 40)
-				} else if strings.Contains(toolDescLower, query) {
-					match = true
-					score = maxInt(score, 30)
-				}
-			}
+		// Search through the catalog servers
+		query := strings.ToLower(strings.TrimSpace(g.preprocessQuery(ctx, params.Query)))
+		var matches []ServerMatch
 
-			// Check image name
-			if server.Image != "" {
-				imageLower := strings.ToLower(server.Image)
-				if strings.Contains(imageLower, query) {
-					match = true
-					score = maxInt(score, 20)
-				}
+		for serverName, server := range configuration.servers {
+			if !params.IncludeDisabled && !configuration.isEnabled(serverName) {
+				continue
 			}
-
-			if match {
+			if score := scoreServer(serverName, withToolDescriptions(configuration, serverName, server), query).Total(); score > 0 {
 				matches = append(matches, ServerMatch{
 					Name:   serverName,
 					Server: server,
@@ -160,11 +133,24 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 			}
 		}
 
+		// Blend in the servers' popularity and the feedback on their tools for this query, if configured
+		matches = rankMatches(matches,
+			popularityBoost(matches, g.FindRankField, g.FindRankWeight),
+			g.findFeedback.boost(params.Query, matches, g.FindFeedbackWeight))
+
+		// Apply custom re-ranking, if any
+		matches = g.reRank(ctx, params.Query, matches)
+
+		// Drop the matches that are not relevant enough
+		matches, filteredOut := filterByMinScore(matches, params.MinScore)
+
 		// Limit results
 		if len(matches) > params.Limit {
 			matches = matches[:params.Limit]
 		}
 
+		g.findLog.record(params.Query, params.Limit, params.MinScore, matches, configuration.secrets)
+
 		// Format results
 		var results []map[string]any
 		for _, match := range matches {
@@ -176,11 +162,26 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 				serverInfo["description"] = match.Server.Description
 			}
 
-			if len(match.Server.Secrets) > 0 {
-				var secrets []string
-				for _, secret := range match.Server.Secrets {
-					secrets = append(secrets, secret.Name)
+			// A bundle requires the secrets of all its members
+			servers := []catalog.Server{match.Server}
+			if match.Server.IsBundle() {
+				serverInfo["type"] = "bundle"
+				serverInfo["members"] = match.Server.Members
+				servers = nil
+				for _, member := range match.Server.Members {
+					servers = append(servers, configuration.servers[member])
 				}
+			}
+
+			var secrets []string
+			for _, server := range servers {
+				for _, secret := range server.Secrets {
+					if !slices.Contains(secrets, secret.Name) {
+						secrets = append(secrets, secret.Name)
+					}
+				}
+			}
+			if len(secrets) > 0 {
 				serverInfo["required_secrets"] = secrets
 			}
 
@@ -190,6 +191,10 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 
 			serverInfo["long_lived"] = match.Server.LongLived
 
+			if !configuration.isEnabled(match.Name) {
+				serverInfo["enabled"] = false
+			}
+
 			results = append(results, serverInfo)
 		}
 
@@ -198,15 +203,19 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 			"total_matches": len(results),
 			"servers":       results,
 		}
+		if filteredOut > 0 {
+			response["filtered_out"] = filteredOut
+			response["note"] = fmt.Sprintf("%d server(s) scored below min_score. Lower min_score or broaden the query to see them.", filteredOut)
+		}
 
-		responseBytes, err := json.Marshal(response)
+		responseBytes, err := g.marshalToolResponse(response, false)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal response: %w", err)
+			return nil, err
 		}
 
-		return &mcp.CallToolResult{
-			Content: []mcp.Content{&mcp.TextContent{Text: string(responseBytes)}},
-		}, nil
+		cache.put(cacheKey, string(responseBytes), matches)
+
+		return g.jsonResult(responseBytes), nil
 	}
 
 	return &ToolRegistration{
@@ -215,6 +224,84 @@ func (g *Gateway) createMcpFindTool(configuration Configuration) *ToolRegistrati
 	}
 }
 
+// createExplainMatchTool implements a tool explaining how mcp-find scores a server for a query
+func (g *Gateway) createExplainMatchTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "explain-match",
+		Description: "Explain how mcp-find scores a server for a query. Reports the score of each field (name, title, description, tools, image) and of the words of the query (keywords) to help understand why a server was or wasn't found.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "Search query, as passed to mcp-find",
+				},
+				"server": {
+					Type:        "string",
+					Description: "Name of the MCP server to explain the score of",
+				},
+			},
+			Required: []string{"query", "server"},
+		},
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Parse parameters
+		var params struct {
+			Query  string `json:"query"`
+			Server string `json:"server"`
+		}
+
+		if req.Params.Arguments == nil {
+			return nil, fmt.Errorf("missing arguments")
+		}
+
+		paramsBytes, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+		}
+
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Query == "" {
+			return nil, fmt.Errorf("query parameter is required")
+		}
+
+		if params.Server == "" {
+			return nil, fmt.Errorf("server parameter is required")
+		}
+
+		serverName := strings.TrimSpace(params.Server)
+		configuration := g.currentConfiguration()
+		server, found := configuration.servers[serverName]
+		if !found {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Server '%s' not found in the catalog.", serverName),
+				}},
+			}, nil
+		}
+
+		scores := scoreServer(serverName, withToolDescriptions(configuration, serverName, server), strings.ToLower(strings.TrimSpace(g.preprocessQuery(ctx, params.Query))))
+		response := map[string]any{
+			"query":   params.Query,
+			"server":  serverName,
+			"scores":  scores,
+			"score":   scores.Total(),
+			"matched": scores.Total() > 0,
+		}
+
+		return g.jsonToolResult(response, true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("explain-match", handler),
+	}
+}
+
 // ServerMatch represents a search result
 type ServerMatch struct {
 	Name   string
@@ -222,13 +309,183 @@ type ServerMatch struct {
 	Score  int
 }
 
+// maxMatchScore is the score of an exact match on the server name
+const maxMatchScore = 100
+
+// maxKeywordScore is the score of a server matching every word of a multi-word query.
+// It's lower than the score of any match of the whole query.
+const maxKeywordScore = 19
+
+// minKeywordLength is the length under which the words of a query are ignored, e.g. "a", "of"
+const minKeywordLength = 3
+
+// matchScores holds how well each field of a server matches a query (0 means no match)
+type matchScores struct {
+	Name        int `json:"name"`
+	Title       int `json:"title"`
+	Description int `json:"description"`
+	Tools       int `json:"tools"`
+	Image       int `json:"image"`
+	Keywords    int `json:"keywords"`
+}
+
+// Total is the score of the best matching field
+func (m matchScores) Total() int {
+	return max(m.Name, m.Title, m.Description, m.Tools, m.Image, m.Keywords)
+}
+
+// scoreServer scores each field of a server against a lowercase query.
+// Exact matches score higher than partial matches.
+func scoreServer(serverName string, server catalog.Server, query string) matchScores {
+	var scores matchScores
+
+	// Check server name (exact match gets higher score)
+	serverNameLower := strings.ToLower(serverName)
+	if serverNameLower == query {
+		scores.Name = maxMatchScore
+	} else if strings.Contains(serverNameLower, query) {
+		scores.Name = 50
+	}
+
+	// Check server title
+	if server.Title != "" {
+		titleLower := strings.ToLower(server.Title)
+		if titleLower == query {
+			scores.Title = 97
+		} else if strings.Contains(titleLower, query) {
+			scores.Title = 47
+		}
+	}
+
+	// Check server description
+	if server.Description != "" {
+		descriptionLower := strings.ToLower(server.Description)
+		if descriptionLower == query {
+			scores.Description = 95
+		} else if strings.Contains(descriptionLower, query) {
+			scores.Description = 45
+		}
+	}
+
+	// Check if it has tools that might match
+	for _, tool := range server.Tools {
+		toolNameLower := strings.ToLower(tool.Name)
+		toolDescLower := strings.ToLower(tool.Description)
+
+		if toolNameLower == query {
+			scores.Tools = maxInt(scores.Tools, 90)
+		} else if strings.Contains(toolNameLower, query) {
+			scores.Tools = maxInt(scores.Tools, 40)
+		} else if strings.Contains(toolDescLower, query) {
+			scores.Tools = maxInt(scores.Tools, 30)
+		}
+	}
+
+	// Check image name
+	if server.Image != "" {
+		imageLower := strings.ToLower(server.Image)
+		if strings.Contains(imageLower, query) {
+			scores.Image = 20
+		}
+	}
+
+	// Fall back to matching the words of a multi-word query one by one,
+	// e.g. "github issues" matches "Issue tracker for GitHub"
+	if scores.Total() > 0 {
+		return scores
+	}
+	if keywords := queryKeywords(query); len(keywords) > 1 {
+		text := serverSearchText(serverNameLower, server)
+
+		matched := 0
+		for _, keyword := range keywords {
+			if keywordMatches(text, keyword) {
+				matched++
+			}
+		}
+		scores.Keywords = maxKeywordScore * matched / len(keywords)
+	}
+
+	return scores
+}
+
+// queryKeywords splits a lowercase query into its distinct words, ignoring the short ones.
+func queryKeywords(query string) []string {
+	var keywords []string
+	for _, word := range strings.Fields(query) {
+		word = strings.TrimFunc(word, unicode.IsPunct)
+		if utf8.RuneCountInString(word) >= minKeywordLength && !slices.Contains(keywords, word) {
+			keywords = append(keywords, word)
+		}
+	}
+
+	return keywords
+}
+
+// keywordMatches tells whether a lowercase text contains a keyword, or its singular form.
+func keywordMatches(text, keyword string) bool {
+	if strings.Contains(text, keyword) {
+		return true
+	}
+
+	singular, plural := strings.CutSuffix(keyword, "s")
+	return plural && utf8.RuneCountInString(singular) >= minKeywordLength && strings.Contains(text, singular)
+}
+
+// serverSearchText is the lowercase text that the words of a query are matched against.
+func serverSearchText(serverNameLower string, server catalog.Server) string {
+	fields := []string{serverNameLower, server.Title, server.Description}
+	for _, tool := range server.Tools {
+		fields = append(fields, tool.Name, tool.Description)
+	}
+
+	return strings.ToLower(strings.Join(fields, "\n"))
+}
+
+// filterByMinScore keeps the matches whose normalized score (0 to 1) is at least minScore.
+// It returns the kept matches and how many were filtered out.
+func filterByMinScore(matches []ServerMatch, minScore float64) ([]ServerMatch, int) {
+	if minScore <= 0 {
+		return matches, 0
+	}
+
+	var kept []ServerMatch
+	for _, match := range matches {
+		if float64(match.Score)/maxMatchScore >= minScore {
+			kept = append(kept, match)
+		}
+	}
+
+	return kept, len(matches) - len(kept)
+}
+
+// preprocessQuery applies the optional QueryPreprocessor hook to a search query.
+// Without a hook, the query is returned unchanged.
+func (g *Gateway) preprocessQuery(ctx context.Context, query string) string {
+	if g.QueryPreprocessor == nil {
+		return query
+	}
+
+	return g.QueryPreprocessor(ctx, query)
+}
+
+// reRank applies the optional ReRanker hook to the search results.
+// Without a hook, the matches are returned unchanged.
+func (g *Gateway) reRank(ctx context.Context, query string, matches []ServerMatch) []ServerMatch {
+	if g.ReRanker == nil {
+		return matches
+	}
+
+	return g.ReRanker(ctx, query, matches)
+}
+
 func (g *Gateway) createCodeModeTool(_ *clientConfig) *ToolRegistration {
 	tool := &mcp.Tool{
 		Name: "code-mode",
 		Description: `Create a JavaScript-enabled tool that combines multiple MCP server tools. 
 This allows you to write scripts that call multiple tools and combine their results.
-Use the mcp-find tool to find servers and make sure they are are ready with the mcp-add tool. When running
-mcp-add, we don't have to activate the tools.
+Use the mcp-find tool, with include_disabled set, to find the servers that are not enabled yet and make sure
+they are ready with the mcp-add tool. When running mcp-add, we don't have to activate the tools.
 `,
 		InputSchema: &jsonschema.Schema{
 			Type: "object",
@@ -277,8 +534,9 @@ mcp-add, we don't have to activate the tools.
 		}
 
 		// Validate that all requested servers exist
+		configuration := g.currentConfiguration()
 		for _, serverName := range params.Servers {
-			if _, _, found := g.configuration.Find(serverName); !found {
+			if _, _, found := configuration.Find(serverName); !found {
 				return &mcp.CallToolResult{
 					Content: []mcp.Content{&mcp.TextContent{
 						Text: fmt.Sprintf("Error: Server '%s' not found in configuration. Use mcp-find to search for available servers.", serverName),
@@ -290,7 +548,7 @@ mcp-add, we don't have to activate the tools.
 		// Create a tool set adapter for each server
 		var toolSets []codemode.ToolSet
 		for _, serverName := range params.Servers {
-			serverConfig, _, _ := g.configuration.Find(serverName)
+			serverConfig, _, _ := configuration.Find(serverName)
 			toolSets = append(toolSets, &serverToolSetAdapter{
 				gateway:      g,
 				serverName:   serverName,
@@ -471,27 +729,12 @@ func (g *Gateway) createMcpRemoveTool() *ToolRegistration {
 
 		serverName := strings.TrimSpace(params.Name)
 
-		// Remove the server from the current serverNames
-		updatedServerNames := slices.DeleteFunc(slices.Clone(g.configuration.serverNames), func(name string) bool {
-			return name == serverName
-		})
-
-		// Update the current configuration state
-		g.configuration.serverNames = updatedServerNames
-
-		// Stop OAuth provider if this is an OAuth server
-		if g.McpOAuthDcrEnabled {
-			g.stopProvider(serverName)
-		}
-
-		if err := g.removeServerConfiguration(ctx, serverName); err != nil {
-			return nil, fmt.Errorf("failed to remove server configuration: %w", err)
+		if err := g.removeServer(ctx, serverName); err != nil {
+			return nil, err
 		}
 
 		// Persist configuration if session name is set
-		if err := g.configuration.Persist(); err != nil {
-			log.Log("Warning: Failed to persist configuration:", err)
-		}
+		g.persistConfiguration()
 
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{&mcp.TextContent{
@@ -506,6 +749,28 @@ func (g *Gateway) createMcpRemoveTool() *ToolRegistration {
 	}
 }
 
+// removeServer disables a server and removes its capabilities from the session.
+func (g *Gateway) removeServer(ctx context.Context, serverName string) error {
+	// Remove the server from the current serverNames
+	updatedServerNames := slices.DeleteFunc(slices.Clone(g.currentConfiguration().serverNames), func(name string) bool {
+		return name == serverName
+	})
+
+	// Update the current configuration state
+	g.setServerNames(updatedServerNames)
+
+	// Stop OAuth provider if this is an OAuth server
+	if g.McpOAuthDcrEnabled {
+		g.stopProvider(serverName)
+	}
+
+	if err := g.removeServerConfiguration(ctx, serverName); err != nil {
+		return fmt.Errorf("failed to remove server configuration: %w", err)
+	}
+
+	return nil
+}
+
 //nolint:unused
 func (g *Gateway) createMcpRegistryImportTool(configuration Configuration, _ *clientConfig) *ToolRegistration {
 	tool := &mcp.Tool{
@@ -696,6 +961,67 @@ type configValue struct {
 	Key    string `json:"key"`
 	Value  any    `json:"value"`
 	Secret bool   `json:"secret,omitempty"`
+	Coerce *bool  `json:"coerce,omitempty"`
+}
+
+// decodeConfigValue decodes JSON-encoded string values (e.g., arrays passed as strings).
+// If decoding fails, the original string value is kept.
+func decodeConfigValue(value any) any {
+	strValue, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(strValue), &decoded); err != nil {
+		return value
+	}
+
+	return decoded
+}
+
+// coerceConfigValue converts a string value to the type declared by the property's schema
+// (string, integer, number or boolean). Values that can't be converted are kept as strings
+// so that validation reports them. Without a scalar type, JSON-encoded values are decoded.
+func coerceConfigValue(value any, property map[string]any) any {
+	strValue, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	propertyType, _ := property["type"].(string)
+	switch propertyType {
+	case "string":
+		return strValue
+	case "integer":
+		if i, err := strconv.ParseInt(strings.TrimSpace(strValue), 10, 64); err == nil {
+			return float64(i)
+		}
+		return strValue
+	case "number":
+		if f, err := strconv.ParseFloat(strings.TrimSpace(strValue), 64); err == nil {
+			return f
+		}
+		return strValue
+	case "boolean":
+		if b, err := strconv.ParseBool(strings.TrimSpace(strValue)); err == nil {
+			return b
+		}
+		return strValue
+	default:
+		return decodeConfigValue(strValue)
+	}
+}
+
+// configPropertySchema returns the schema of a config key, as declared by the server's config items.
+func configPropertySchema(server catalog.Server, key string) map[string]any {
+	for _, configItem := range server.ConfigItems() {
+		if property, ok := configItem.Properties()[key]; ok {
+			return property
+		}
+	}
+
+	return nil
 }
 
 // formatConfigValue formats a config value for display, handling arrays, objects, and primitives
@@ -728,6 +1054,10 @@ func formatConfigValue(value any) string {
 	}
 }
 
+// errReadOnlyConfig is returned by the tools that would otherwise mutate the configuration
+// when the gateway runs with --read-only-config.
+var errReadOnlyConfig = errors.New("configuration is read-only in this deployment")
+
 // mcpConfigSetTool implements a tool for setting configuration values for MCP servers
 func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 	tool := &mcp.Tool{
@@ -746,19 +1076,27 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 				},
 				"value": {
 					Types:       []string{"string", "number", "boolean", "object", "array"},
-					Description: "Configuration value to set (can be string, number, boolean, object, or array)",
+					Description: "Configuration value to set (can be string, number, boolean, object, or array). Strings can reference the environment variables allowed with --allow-env-reference, with ${env:NAME} or ${env:NAME:-default}, resolved when the server is launched. A variable that is not set requires a default",
 					Items:       &jsonschema.Schema{Type: "object"},
 				},
 				"secret": {
 					Type:        "boolean",
 					Description: "If true, store the value in the secrets file instead of config. The secret name will be server.key (e.g., brave.api_key)",
 				},
+				"coerce": {
+					Type:        "boolean",
+					Description: "If true (default), string values are converted to the type declared by the server's config schema (e.g., \"8080\" to 8080 for an integer)",
+				},
 			},
 			Required: []string{"server", "key", "value"},
 		},
 	}
 
 	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if g.ReadOnlyConfig {
+			return nil, errReadOnlyConfig
+		}
+
 		// Parse parameters
 		var params configValue
 
@@ -796,10 +1134,7 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 			secretName := fmt.Sprintf("%s.%s", serverName, configKey)
 
 			// Update in-memory secrets
-			if g.configuration.secrets == nil {
-				g.configuration.secrets = make(map[string]string)
-			}
-			g.configuration.secrets[secretName] = secretValue
+			g.setSecret(secretName, secretValue)
 
 			// Try to persist to secrets file
 			var persistMessage string
@@ -815,14 +1150,17 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 					}
 
 					if secretsFilePath != "" {
-						// Resolve relative paths to ~/.docker/mcp/
-						resolvedPath, err := config.FilePath(secretsFilePath)
+						// Resolve relative paths to ~/.docker/mcp/, or to the internal tools directory
+						resolvedPath, err := internalToolPath(g.InternalToolsDir, secretsFilePath)
+						if errors.Is(err, errOutsideSandbox) {
+							return nil, fmt.Errorf("refusing to persist secret: %w", err)
+						}
 						if err != nil {
 							log.Log("Warning: Failed to resolve secrets path:", err)
 							persistMessage = " (Note: failed to resolve secrets path)"
 						} else {
 							// Read existing secrets
-							existingSecrets, _ := fbc.readSecretsFromFile(ctx, secretsFilePath)
+							existingSecrets, _ := fbc.readSecretsFromFile(ctx, resolvedPath)
 							if existingSecrets == nil {
 								existingSecrets = make(map[string]string)
 							}
@@ -859,29 +1197,30 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 			}, nil
 		}
 
-		// Decode JSON-encoded values (e.g., arrays passed as strings)
-		finalValue := params.Value
-		if strValue, ok := params.Value.(string); ok {
-			// Try to JSON decode the string value
-			var decoded any
-			if err := json.Unmarshal([]byte(strValue), &decoded); err == nil {
-				// Successfully decoded - use the decoded value
-				finalValue = decoded
+		// Check if server exists in catalog (optional check - we can configure servers that don't exist yet)
+		configuration := g.currentConfiguration()
+		serverConfig, _, serverExists := configuration.Find(serverName)
+
+		// Convert string values to the type declared by the server's config schema,
+		// or decode JSON-encoded values (e.g., arrays passed as strings)
+		var finalValue any
+		if params.Coerce == nil || *params.Coerce {
+			var property map[string]any
+			if serverConfig != nil {
+				property = configPropertySchema(serverConfig.Spec, configKey)
 			}
-			// If decoding fails, keep the original string value
+			finalValue = coerceConfigValue(params.Value, property)
+		} else {
+			finalValue = decodeConfigValue(params.Value)
 		}
 
-		// Check if server exists in catalog (optional check - we can configure servers that don't exist yet)
-		_, _, serverExists := g.configuration.Find(serverName)
-
-		// Initialize the server's config map if it doesn't exist
-		if g.configuration.config[serverName] == nil {
-			g.configuration.config[serverName] = make(map[string]any)
+		// The environment variables referenced must be resolvable when the server is launched
+		if err := checkEnvReferences(finalValue, configuration.allowedEnv); err != nil {
+			return nil, err
 		}
 
 		// Set the configuration value
-		oldValue := g.configuration.config[serverName][configKey]
-		g.configuration.config[serverName][configKey] = finalValue
+		oldValue := g.setConfigValue(serverName, configKey, finalValue)
 
 		// Format the value for display
 		valueStr := formatConfigValue(finalValue)
@@ -891,9 +1230,7 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 		log.Log(fmt.Sprintf("  - Set config for server '%s': %s = %s", serverName, configKey, valueStr))
 
 		// Persist configuration if session name is set
-		if err := g.configuration.Persist(); err != nil {
-			log.Log("Warning: Failed to persist configuration:", err)
-		}
+		g.persistConfiguration()
 
 		var resultMessage string
 		if oldValue != nil {
@@ -903,7 +1240,11 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 		}
 
 		if !serverExists {
-			resultMessage += fmt.Sprintf(" (Note: server '%s' is not in the current catalog)", serverName)
+			if similar := configuration.FindSimilar(serverName); len(similar) > 0 {
+				resultMessage += fmt.Sprintf(" (Note: server '%s' is not in the current catalog, did you mean %s?)", serverName, quoteServerNames(similar))
+			} else {
+				resultMessage += fmt.Sprintf(" (Note: server '%s' is not in the current catalog. Use mcp-find to search for available servers.)", serverName)
+			}
 		}
 
 		return &mcp.CallToolResult{
@@ -919,6 +1260,19 @@ func (g *Gateway) createMcpConfigSetTool(_ *clientConfig) *ToolRegistration {
 	}
 }
 
+// quoteServerNames formats server names for a "did you mean" suggestion: 'a', 'b' or 'c'
+func quoteServerNames(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
+
 // createMcpSessionNameTool implements a tool for setting the session name
 //
 //nolint:unused
@@ -973,10 +1327,13 @@ func (g *Gateway) createMcpSessionNameTool() *ToolRegistration {
 		}
 
 		// Set the session name
-		g.configuration.SessionName = sessionName
+		g.updateConfiguration(func(configuration *Configuration) {
+			configuration.SessionName = sessionName
+		})
 
 		// Persist the current configuration to the session directory
-		if err := g.configuration.Persist(); err != nil {
+		configuration := g.currentConfiguration()
+		if err := configuration.Persist(g.InternalToolsDir); err != nil {
 			return nil, fmt.Errorf("failed to persist configuration: %w", err)
 		}
 
@@ -1108,6 +1465,138 @@ func (g *Gateway) createMcpExecTool() *ToolRegistration {
 	}
 }
 
+// createListToolsTool implements a tool that lists every tool currently exposed by the gateway
+func (g *Gateway) createListToolsTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "list-tools",
+		Description: "List all the tools currently exposed by the gateway, in tools/list format. Optionally filter by the name of the MCP server providing them.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"server": {
+					Type:        "string",
+					Description: "Only list the tools of this MCP server",
+				},
+			},
+		},
+	}
+
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Parse parameters
+		var params struct {
+			Server string `json:"server"`
+		}
+
+		if req.Params.Arguments != nil {
+			paramsBytes, err := json.Marshal(req.Params.Arguments)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+			}
+
+			if err := json.Unmarshal(paramsBytes, &params); err != nil {
+				return nil, fmt.Errorf("failed to parse arguments: %w", err)
+			}
+		}
+
+		return g.jsonToolResult(mcp.ListToolsResult{
+			Tools: g.listTools(strings.TrimSpace(params.Server)),
+		}, false)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("list-tools", handler),
+	}
+}
+
+// listTools returns the registered tools sorted by name, optionally only those of a given server.
+func (g *Gateway) listTools(serverName string) []*mcp.Tool {
+	tools := []*mcp.Tool{}
+	for _, toolReg := range g.GetToolRegistrationsSorted() {
+		if serverName != "" && toolReg.ServerName != serverName {
+			continue
+		}
+		tools = append(tools, toolReg.Tool)
+	}
+
+	return tools
+}
+
+// createDescribeToolTool implements a tool returning everything needed to call a tool
+func (g *Gateway) createDescribeToolTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "describe-tool",
+		Description: "Describe a tool exposed by the gateway: its full input schema, description and the name of the MCP server providing it. Use it to learn how to call a tool found with mcp-find or list-tools.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"name": {
+					Type:        "string",
+					Description: "Name of the tool to describe",
+				},
+			},
+			Required: []string{"name"},
+		},
+	}
+
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Parse parameters
+		var params struct {
+			Name string `json:"name"`
+		}
+
+		if req.Params.Arguments == nil {
+			return nil, fmt.Errorf("missing arguments")
+		}
+
+		paramsBytes, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+		}
+
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Name == "" {
+			return nil, fmt.Errorf("name parameter is required")
+		}
+
+		toolName := strings.TrimSpace(params.Name)
+
+		g.capabilitiesMu.RLock()
+		toolReg, found := g.toolRegistrations[toolName]
+		g.capabilitiesMu.RUnlock()
+
+		if !found {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Tool '%s' not found in current session. Use list-tools to see the available tools.", toolName),
+				}},
+			}, nil
+		}
+
+		response := map[string]any{
+			"name":        toolReg.Tool.Name,
+			"description": toolReg.Tool.Description,
+			"inputSchema": toolReg.Tool.InputSchema,
+		}
+		if toolReg.ServerName != "" {
+			response["server"] = toolReg.ServerName
+		}
+		if toolReg.Tool.Annotations != nil {
+			response["annotations"] = toolReg.Tool.Annotations
+		}
+
+		return g.jsonToolResult(response, false)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("describe-tool", handler),
+	}
+}
+
 //nolint:unused // mcpCatalogTool implements a tool for viewing information about the currently attached catalog
 func (g *Gateway) _createMcpCatalogTool() *ToolRegistration {
 	tool := &mcp.Tool{
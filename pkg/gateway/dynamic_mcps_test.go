@@ -5,12 +5,18 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
 )
 
 func TestMcpExecTool(t *testing.T) {
@@ -416,3 +422,326 @@ func TestShortenURLWithBitly(t *testing.T) {
 		}
 	})
 }
+
+func TestCoerceConfigValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		property map[string]any
+		expected any
+	}{
+		{"integer", "8080", map[string]any{"type": "integer"}, float64(8080)},
+		{"invalid integer", "80.5", map[string]any{"type": "integer"}, "80.5"},
+		{"number", "0.75", map[string]any{"type": "number"}, 0.75},
+		{"boolean", "true", map[string]any{"type": "boolean"}, true},
+		{"invalid boolean", "yes", map[string]any{"type": "boolean"}, "yes"},
+		{"string stays a string", "8080", map[string]any{"type": "string"}, "8080"},
+		{"non string value", float64(42), map[string]any{"type": "string"}, float64(42)},
+		{"no schema decodes json", `["a","b"]`, nil, []any{"a", "b"}},
+		{"no schema keeps plain strings", "hello", nil, "hello"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, coerceConfigValue(tt.value, tt.property))
+		})
+	}
+}
+
+func TestListTools(t *testing.T) {
+	g := &Gateway{
+		toolRegistrations: map[string]ToolRegistration{
+			"search":        {ServerName: "duckduckgo", Tool: &mcp.Tool{Name: "search"}},
+			"fetch_content": {ServerName: "duckduckgo", Tool: &mcp.Tool{Name: "fetch_content"}},
+			"get_me":        {ServerName: "github", Tool: &mcp.Tool{Name: "get_me"}},
+			"mcp-find":      {Tool: &mcp.Tool{Name: "mcp-find"}},
+		},
+	}
+
+	toolNames := func(tools []*mcp.Tool) []string {
+		var names []string
+		for _, tool := range tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"fetch_content", "get_me", "mcp-find", "search"}, toolNames(g.listTools("")))
+	assert.Equal(t, []string{"fetch_content", "search"}, toolNames(g.listTools("duckduckgo")))
+	assert.Empty(t, g.listTools("unknown"))
+}
+
+func TestFilterByMinScore(t *testing.T) {
+	matches := []ServerMatch{
+		{Name: "github", Score: 100},
+		{Name: "github-chat", Score: 50},
+		{Name: "git", Score: 20},
+	}
+
+	kept, filteredOut := filterByMinScore(matches, 0.5)
+	assert.Len(t, kept, 2)
+	assert.Equal(t, 1, filteredOut)
+
+	kept, filteredOut = filterByMinScore(matches, 0)
+	assert.Len(t, kept, 3)
+	assert.Zero(t, filteredOut)
+
+	kept, filteredOut = filterByMinScore(matches, 1)
+	assert.Equal(t, []ServerMatch{{Name: "github", Score: 100}}, kept)
+	assert.Equal(t, 2, filteredOut)
+}
+
+func TestScoreServer(t *testing.T) {
+	server := catalog.Server{
+		Title:       "GitHub",
+		Description: "Official GitHub MCP Server",
+		Image:       "ghcr.io/github/github-mcp-server",
+		Tools: []catalog.Tool{
+			{Name: "create_issue", Description: "Create a new issue in a GitHub repository"},
+		},
+	}
+
+	scores := scoreServer("github-official", server, "github")
+	assert.Equal(t, matchScores{Name: 50, Title: 97, Description: 45, Tools: 30, Image: 20}, scores)
+	assert.Equal(t, 97, scores.Total())
+
+	scores = scoreServer("github-official", server, "create_issue")
+	assert.Equal(t, matchScores{Tools: 90}, scores)
+
+	assert.Zero(t, scoreServer("github-official", server, "slack").Total())
+}
+
+func TestScoreServerKeywords(t *testing.T) {
+	server := catalog.Server{
+		Title:       "Tracker",
+		Description: "Issue tracker for GitHub",
+	}
+
+	scores := scoreServer("tracker", server, "github issues")
+	assert.Equal(t, matchScores{Keywords: maxKeywordScore}, scores)
+
+	scores = scoreServer("tracker", server, "github pull requests")
+	assert.Equal(t, matchScores{Keywords: maxKeywordScore / 3}, scores)
+
+	// Words are only matched one by one when the whole query doesn't match
+	scores = scoreServer("tracker", server, "issue tracker")
+	assert.Equal(t, matchScores{Description: 45}, scores)
+
+	assert.Zero(t, scoreServer("tracker", server, "slack messages").Total())
+}
+
+func TestExplainMatchReadsCurrentConfiguration(t *testing.T) {
+	g := &Gateway{configuration: Configuration{servers: map[string]catalog.Server{}}}
+	explain := g.createExplainMatchTool()
+
+	// A server added after the tool was created is explained
+	g.setConfiguration(Configuration{servers: map[string]catalog.Server{"tracker": {Description: "Issue tracker for GitHub"}}})
+
+	result := callInternalTool(t, explain, map[string]any{"query": "github issues", "server": "tracker"})
+	var response struct {
+		Matched bool `json:"matched"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	assert.True(t, response.Matched)
+}
+
+func TestPreprocessQuery(t *testing.T) {
+	g := &Gateway{}
+	assert.Equal(t, "Please find GitHub", g.preprocessQuery(t.Context(), "Please find GitHub"))
+
+	g.QueryPreprocessor = func(_ context.Context, query string) string {
+		return strings.TrimPrefix(query, "Please find ")
+	}
+	assert.Equal(t, "GitHub", g.preprocessQuery(t.Context(), "Please find GitHub"))
+}
+
+func TestQueryKeywords(t *testing.T) {
+	assert.Equal(t, []string{"find", "github-issues", "github"}, queryKeywords("find a github-issues, github"))
+	assert.Empty(t, queryKeywords("a of"))
+}
+
+// callInternalTool calls one of the gateway's own tools the way a client does, telemetry included.
+func callInternalTool(t *testing.T, registration *ToolRegistration, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+
+	result, err := callInternalToolErr(t, registration, args)
+	require.NoError(t, err)
+	return result
+}
+
+// callInternalToolErr calls an internal tool, returning the error of its handler
+func callInternalToolErr(t *testing.T, registration *ToolRegistration, args map[string]any) (*mcp.CallToolResult, error) {
+	t.Helper()
+	setupTestTelemetry(t)
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil)
+	server.AddTool(registration.Tool, registration.Handler)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := server.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	return session.CallTool(t.Context(), &mcp.CallToolParams{Name: registration.Tool.Name, Arguments: args})
+}
+
+func TestMcpFindIncludeDisabled(t *testing.T) {
+	g := &Gateway{
+		Options: Options{FindCacheTTL: time.Minute},
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers: map[string]catalog.Server{
+				"github": {Description: "Manage GitHub issues"},
+				"gitlab": {Description: "Manage GitLab issues"},
+			},
+		},
+	}
+	mcpFind := g.createMcpFindTool()
+
+	find := func(includeDisabled bool) map[string]any {
+		result := callInternalTool(t, mcpFind, map[string]any{"query": "issues", "include_disabled": includeDisabled})
+		require.False(t, result.IsError)
+
+		found := map[string]any{}
+		var response struct {
+			Servers []map[string]any `json:"servers"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+		for _, server := range response.Servers {
+			found[server["name"].(string)] = server["enabled"]
+		}
+		return found
+	}
+
+	assert.Equal(t, map[string]any{"github": nil}, find(false))
+	assert.Equal(t, map[string]any{"github": nil, "gitlab": false}, find(true))
+
+	// What mcp-add does: the server is searched right away, despite the cache
+	g.setServerNames([]string{"github", "gitlab"})
+	assert.Equal(t, map[string]any{"github": nil, "gitlab": nil}, find(false))
+}
+
+func TestMcpFindLogsCachedSearches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "find.jsonl")
+	searchLog, err := openFindLog(path)
+	require.NoError(t, err)
+
+	g := &Gateway{
+		Options: Options{FindCacheTTL: time.Minute},
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers: map[string]catalog.Server{
+				"github": {Description: "Manage GitHub issues"},
+			},
+		},
+		findLog: searchLog,
+	}
+	mcpFind := g.createMcpFindTool()
+
+	for range 2 {
+		result := callInternalTool(t, mcpFind, map[string]any{"query": "issues"})
+		require.False(t, result.IsError)
+	}
+	require.NoError(t, searchLog.Close())
+
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[1], `"candidates":[{"name":"github"`)
+}
+
+func TestMcpFindReRanker(t *testing.T) {
+	var reRankedQuery string
+	var candidates []string
+	g := &Gateway{
+		ReRanker: func(_ context.Context, query string, matches []ServerMatch) []ServerMatch {
+			reRankedQuery = query
+			for _, match := range matches {
+				candidates = append(candidates, match.Name)
+			}
+			slices.Reverse(matches)
+			return matches
+		},
+		configuration: Configuration{
+			serverNames: []string{"github", "gitlab", "slack"},
+			servers: map[string]catalog.Server{
+				"github": {Title: "GitHub Issues"},
+				"gitlab": {Description: "Mirror GitHub issues to GitLab"},
+				"slack":  {Description: "Send Slack messages"},
+			},
+		},
+	}
+
+	result := callInternalTool(t, g.createMcpFindTool(), map[string]any{"query": "github issues"})
+	require.False(t, result.IsError)
+
+	var response struct {
+		Servers []struct {
+			Name string `json:"name"`
+		} `json:"servers"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+
+	// The hook gets the scored candidates, best first, and its order is kept
+	assert.Equal(t, "github issues", reRankedQuery)
+	assert.Equal(t, []string{"github", "gitlab"}, candidates)
+	require.Len(t, response.Servers, 2)
+	assert.Equal(t, "gitlab", response.Servers[0].Name)
+	assert.Equal(t, "github", response.Servers[1].Name)
+}
+
+func TestReRankWithoutHook(t *testing.T) {
+	matches := []ServerMatch{{Name: "github", Score: 2}, {Name: "gitlab", Score: 1}}
+
+	assert.Equal(t, matches, (&Gateway{}).reRank(t.Context(), "github", matches))
+}
+
+func TestMcpConfigSetReadOnly(t *testing.T) {
+	g := &Gateway{
+		Options: Options{ReadOnlyConfig: true},
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers: map[string]catalog.Server{
+				"github": {Image: "mcp/github"},
+			},
+			config: map[string]map[string]any{},
+		},
+	}
+
+	_, err := callInternalToolErr(t, g.createMcpConfigSetTool(nil), map[string]any{"server": "github", "key": "owner", "value": "docker"})
+	require.ErrorContains(t, err, errReadOnlyConfig.Error())
+
+	assert.Empty(t, g.configuration.config)
+}
+
+func TestMcpConfigSetEnvReferences(t *testing.T) {
+	t.Setenv("MCP_TEST_OWNER", "docker")
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers: map[string]catalog.Server{
+				"github": {Image: "mcp/github"},
+			},
+			config:     map[string]map[string]any{},
+			allowedEnv: []string{"MCP_TEST_OWNER", "MCP_TEST_UNSET"},
+		},
+	}
+	configSet := g.createMcpConfigSetTool(nil)
+
+	for _, value := range []string{"${env:MCP_TEST_OWNER}", "${env:MCP_TEST_UNSET:-docker}"} {
+		result := callInternalTool(t, configSet, map[string]any{"server": "github", "key": "owner", "value": value})
+		require.False(t, result.IsError)
+		assert.Equal(t, value, g.currentConfiguration().config["github"]["owner"])
+	}
+
+	_, err := callInternalToolErr(t, configSet, map[string]any{"server": "github", "key": "owner", "value": "${env:MCP_TEST_UNSET}"})
+	require.ErrorContains(t, err, "environment variable MCP_TEST_UNSET referenced in config is not set")
+
+	_, err = callInternalToolErr(t, configSet, map[string]any{"server": "github", "key": "token", "value": "${env:HOME:-none}"})
+	require.ErrorContains(t, err, "environment variable HOME referenced in config is not allowed")
+
+	assert.Equal(t, map[string]any{"owner": "${env:MCP_TEST_UNSET:-docker}"}, g.currentConfiguration().config["github"])
+}
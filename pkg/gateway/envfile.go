@@ -0,0 +1,127 @@
+package gateway
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// secretReference matches `${secret:NAME}` in the values of an env file.
+var secretReference = regexp.MustCompile(`\$\{secret:([^}]+)\}`)
+
+// parseDotEnv parses KEY=value lines, skipping comments and blank lines.
+// It's the format of the secrets files.
+func parseDotEnv(ctx context.Context, buf []byte) (map[string]string, error) {
+	values := map[string]string{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(buf))
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid line: %s", line)
+		}
+
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+// secretLookup returns the value of a secret and whether it's set.
+type secretLookup func(ctx context.Context, name string) (string, bool)
+
+// readEnvFile reads a server's env file, absolute or relative to ~/.docker/mcp/,
+// and replaces the secret references in its values.
+func readEnvFile(ctx context.Context, path string, lookupSecret secretLookup) (map[string]string, error) {
+	resolvedPath, err := config.FilePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buf, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := parseDotEnv(ctx, buf)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, value := range env {
+		var missing []string
+		env[name] = secretReference.ReplaceAllStringFunc(value, func(reference string) string {
+			secretName := secretReference.FindStringSubmatch(reference)[1]
+			secretValue, found := lookupSecret(ctx, secretName)
+			if !found {
+				missing = append(missing, secretName)
+			}
+			return secretValue
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("secret %s referenced by %s is not set", strings.Join(missing, ", "), name)
+		}
+	}
+
+	return env, nil
+}
+
+// readEnvFiles reads the env files of the servers' registry entries. A missing or invalid file
+// doesn't fail the whole configuration: its error is returned for its server, which can't start.
+func readEnvFiles(ctx context.Context, registry map[string]config.Tile, lookupSecret secretLookup) (map[string]map[string]string, map[string]error) {
+	envFiles := map[string]map[string]string{}
+	errs := map[string]error{}
+
+	for _, serverName := range slices.Sorted(maps.Keys(registry)) {
+		path := registry[serverName].EnvFile
+		if path == "" {
+			continue
+		}
+
+		env, err := readEnvFile(ctx, path, lookupSecret)
+		if err != nil {
+			errs[serverName] = fmt.Errorf("reading env file %s: %w", path, err)
+			log.Warn(fmt.Sprintf("server %s can't start: %s", serverName, errs[serverName]))
+			continue
+		}
+		envFiles[serverName] = env
+	}
+
+	return envFiles, errs
+}
+
+// extraEnv returns the environment added to a server's container: the values of its env file,
+// overridden by the registry's extraEnv.
+func (c *Configuration) extraEnv(serverName string) map[string]string {
+	tile := c.registry[serverName]
+	if len(c.envFiles[serverName]) == 0 {
+		return tile.ExtraEnv
+	}
+
+	env := maps.Clone(c.envFiles[serverName])
+	maps.Copy(env, tile.ExtraEnv)
+	return env
+}
@@ -0,0 +1,83 @@
+package gateway
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+// lookupIn looks the secrets up in a map
+func lookupIn(secrets map[string]string) secretLookup {
+	return func(_ context.Context, name string) (string, bool) {
+		value, found := secrets[name]
+		return value, found
+	}
+}
+
+func TestReadEnvFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "github.env")
+	require.NoError(t, os.WriteFile(path, []byte("# GitHub settings\nGITHUB_HOST=github.example.com\nGITHUB_TOKEN=${secret:github.token}\n\nLOG_LEVEL=info\n"), 0o600))
+
+	registry := map[string]config.Tile{
+		"github": {Ref: "github", EnvFile: path, ExtraEnv: map[string]string{"LOG_LEVEL": "debug"}},
+		"fetch":  {Ref: "fetch"},
+	}
+	secrets := lookupIn(map[string]string{"github.token": "ghp_secret"})
+
+	envFiles, errs := readEnvFiles(t.Context(), registry, secrets)
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]map[string]string{
+		"github": {"GITHUB_HOST": "github.example.com", "GITHUB_TOKEN": "ghp_secret", "LOG_LEVEL": "info"},
+	}, envFiles)
+
+	// The registry's extraEnv overrides the env file
+	configuration := Configuration{registry: registry, envFiles: envFiles}
+	assert.Equal(t, map[string]string{"GITHUB_HOST": "github.example.com", "GITHUB_TOKEN": "ghp_secret", "LOG_LEVEL": "debug"}, configuration.extraEnv("github"))
+	assert.Empty(t, configuration.extraEnv("fetch"))
+
+	// Missing secret
+	envFiles, errs = readEnvFiles(t.Context(), registry, lookupIn(nil))
+	require.ErrorContains(t, errs["github"], "secret github.token referenced by GITHUB_TOKEN is not set")
+	assert.Empty(t, envFiles)
+
+	// Missing file, only its server fails
+	otherPath := filepath.Join(t.TempDir(), "fetch.env")
+	require.NoError(t, os.WriteFile(otherPath, []byte("USER_AGENT=mcp\n"), 0o600))
+	registry["github"] = config.Tile{Ref: "github", EnvFile: filepath.Join(t.TempDir(), "missing.env")}
+	registry["fetch"] = config.Tile{Ref: "fetch", EnvFile: otherPath}
+	envFiles, errs = readEnvFiles(t.Context(), registry, secrets)
+	require.ErrorContains(t, errs["github"], "missing.env")
+	assert.NotContains(t, errs, "fetch")
+	assert.Equal(t, map[string]map[string]string{"fetch": {"USER_AGENT": "mcp"}}, envFiles)
+}
+
+func TestLookupSecretReadsTheProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "secrets.env")
+	require.NoError(t, os.WriteFile(path, []byte("slack.token=xoxb\n"), 0o600))
+
+	c := &FileBasedConfiguration{SecretsPath: path}
+	lookup := c.lookupSecret(map[string]string{"github.token": "ghp_secret"})
+
+	value, found := lookup(t.Context(), "github.token")
+	assert.True(t, found)
+	assert.Equal(t, "ghp_secret", value)
+
+	// Not read upfront, e.g. the secret of a server that's not enabled
+	value, found = lookup(t.Context(), "slack.token")
+	assert.True(t, found)
+	assert.Equal(t, "xoxb", value)
+
+	_, found = lookup(t.Context(), "unknown")
+	assert.False(t, found)
+}
+
+func TestParseDotEnvInvalidLine(t *testing.T) {
+	_, err := parseDotEnv(t.Context(), []byte("A=1\nNOT_A_VARIABLE\n"))
+	require.EqualError(t, err, "invalid line: NOT_A_VARIABLE")
+}
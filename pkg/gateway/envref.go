@@ -0,0 +1,97 @@
+package gateway
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// envReference matches `${env:NAME}` and `${env:NAME:-default}` in config values.
+var envReference = regexp.MustCompile(`\$\{env:([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// resolveEnvReferences replaces the environment variable references in a config value
+// with their value in the process environment. The stored config is left untouched.
+// Only the variables in the allowlist are resolved, the others are considered not set.
+func resolveEnvReferences(value any, allowed []string) any {
+	switch v := value.(type) {
+	case string:
+		return envReference.ReplaceAllStringFunc(v, func(reference string) string {
+			value, err := lookupEnvReference(reference, allowed)
+			if err != nil {
+				log.Warn(err.Error())
+			}
+			return value
+		})
+	case map[string]any:
+		if v == nil {
+			return v
+		}
+		resolved := make(map[string]any, len(v))
+		for key, item := range v {
+			resolved[key] = resolveEnvReferences(item, allowed)
+		}
+		return resolved
+	case []any:
+		if v == nil {
+			return v
+		}
+		resolved := make([]any, len(v))
+		for i, item := range v {
+			resolved[i] = resolveEnvReferences(item, allowed)
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+// lookupEnvReference returns the value of an environment variable reference. It fails when the
+// variable is not in the allowlist, or is not set and the reference has no default, in which
+// case the default, maybe empty, is returned.
+func lookupEnvReference(reference string, allowed []string) (string, error) {
+	match := envReference.FindStringSubmatch(reference)
+	name, defaultValue := match[1], match[2]
+	if !slices.Contains(allowed, name) {
+		return defaultValue, fmt.Errorf("environment variable %s referenced in config is not allowed (use --allow-env-reference to allow it)", name)
+	}
+	if envValue, ok := os.LookupEnv(name); ok {
+		return envValue, nil
+	}
+	if !hasEnvDefault(reference) {
+		return defaultValue, fmt.Errorf("environment variable %s referenced in config is not set", name)
+	}
+	return defaultValue, nil
+}
+
+// checkEnvReferences rejects the first environment variable reference of a config value that
+// can't be resolved when the server is launched.
+func checkEnvReferences(value any, allowed []string) error {
+	switch v := value.(type) {
+	case string:
+		for _, reference := range envReference.FindAllString(v, -1) {
+			if _, err := lookupEnvReference(reference, allowed); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, item := range v {
+			if err := checkEnvReferences(item, allowed); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, item := range v {
+			if err := checkEnvReferences(item, allowed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func hasEnvDefault(reference string) bool {
+	return envReference.FindStringSubmatchIndex(reference)[4] != -1
+}
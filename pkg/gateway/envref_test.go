@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEnvReferences(t *testing.T) {
+	t.Setenv("MCP_TEST_REGION", "eu-west-1")
+	t.Setenv("MCP_TEST_TOKEN", "secret")
+
+	config := map[string]any{
+		"region":   "${env:MCP_TEST_REGION}",
+		"endpoint": "https://${env:MCP_TEST_UNSET:-localhost}:8080",
+		"port":     8080,
+		"paths":    []any{"${env:MCP_TEST_REGION}/data"},
+		"missing":  "${env:MCP_TEST_UNSET}",
+		"token":    "${env:MCP_TEST_TOKEN:-none}",
+	}
+
+	resolved := resolveEnvReferences(config, []string{"MCP_TEST_REGION", "MCP_TEST_UNSET"})
+
+	assert.Equal(t, map[string]any{
+		"region":   "eu-west-1",
+		"endpoint": "https://localhost:8080",
+		"port":     8080,
+		"paths":    []any{"eu-west-1/data"},
+		"missing":  "",
+		"token":    "none",
+	}, resolved)
+	assert.Equal(t, "${env:MCP_TEST_REGION}", config["region"])
+}
+
+func TestResolveEnvReferencesEmptyDefault(t *testing.T) {
+	assert.Equal(t, "prefix-", resolveEnvReferences("prefix-${env:MCP_TEST_UNSET:-}", []string{"MCP_TEST_UNSET"}))
+}
+
+func TestResolveEnvReferencesNotAllowed(t *testing.T) {
+	t.Setenv("MCP_TEST_TOKEN", "secret")
+
+	assert.Empty(t, resolveEnvReferences("${env:MCP_TEST_TOKEN}", nil))
+	assert.Equal(t, "default", resolveEnvReferences("${env:MCP_TEST_TOKEN:-default}", []string{"OTHER"}))
+}
+
+func TestCheckEnvReferences(t *testing.T) {
+	t.Setenv("MCP_TEST_TOKEN", "secret")
+	allowed := []string{"MCP_TEST_TOKEN", "MCP_TEST_UNSET"}
+
+	require.NoError(t, checkEnvReferences("plain value", allowed))
+	require.NoError(t, checkEnvReferences(8080, allowed))
+	require.NoError(t, checkEnvReferences("${env:MCP_TEST_TOKEN}", allowed))
+	require.NoError(t, checkEnvReferences("https://${env:MCP_TEST_UNSET:-localhost}", allowed))
+	require.NoError(t, checkEnvReferences(map[string]any{"key": []any{"${env:MCP_TEST_TOKEN}"}}, allowed))
+
+	require.EqualError(t, checkEnvReferences("${env:MCP_TEST_UNSET}", allowed), "environment variable MCP_TEST_UNSET referenced in config is not set")
+	require.EqualError(t, checkEnvReferences(map[string]any{"key": []any{"${env:OPENAI_API_KEY:-none}"}}, allowed),
+		"environment variable OPENAI_API_KEY referenced in config is not allowed (use --allow-env-reference to allow it)")
+}
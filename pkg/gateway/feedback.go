@@ -0,0 +1,253 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// findFeedbackFile is where the tool feedback is persisted, relative to ~/.docker/mcp/
+const findFeedbackFile = "tool-feedback.json"
+
+// findFeedback counts, for each normalized mcp-find query, how many times each tool of each server
+// was reported as the one that was useful. Counts are persisted so that they accumulate over time.
+type findFeedback struct {
+	path string
+
+	mu     sync.Mutex
+	counts map[string]map[string]map[string]int // query -> server -> tool -> count
+}
+
+// validateFindFeedbackWeight checks the feedback weight, and that it leaves a share to the relevance
+// once added to the weight of the popularity, if mcp-find ranks on it.
+func validateFindFeedbackWeight(weight, rankWeight float64) error {
+	if weight < 0 || weight > 1 {
+		return fmt.Errorf("find feedback weight must be between 0 and 1, got %g", weight)
+	}
+	if weight+rankWeight > 1 {
+		return fmt.Errorf("find feedback weight and find rank weight must add up to at most 1, got %g and %g", weight, rankWeight)
+	}
+	return nil
+}
+
+// loadFindFeedback loads the feedback from ~/.docker/mcp/, or from the internal tools directory
+func loadFindFeedback(sandboxDir string) (*findFeedback, error) {
+	path, err := internalToolPath(sandboxDir, findFeedbackFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFindFeedback(path)
+}
+
+// newFindFeedback loads the persisted counts, if any.
+func newFindFeedback(path string) (*findFeedback, error) {
+	feedback := &findFeedback{
+		path:   path,
+		counts: map[string]map[string]map[string]int{},
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return feedback, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(buf, &feedback.counts); err != nil {
+		return nil, fmt.Errorf("parsing tool feedback file %s: %w", path, err)
+	}
+
+	return feedback, nil
+}
+
+// record counts a server's tool as useful for a query and returns the new count.
+func (f *findFeedback) record(query, serverName, toolName string) (int, error) {
+	query = normalizeFindQuery(query)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.counts[query] == nil {
+		f.counts[query] = map[string]map[string]int{}
+	}
+	if f.counts[query][serverName] == nil {
+		f.counts[query][serverName] = map[string]int{}
+	}
+	f.counts[query][serverName][toolName]++
+
+	return f.counts[query][serverName][toolName], f.persist()
+}
+
+func (f *findFeedback) persist() error {
+	buf, err := json.MarshalIndent(f.counts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(f.path, buf, 0o644)
+}
+
+// boost boosts the matches on how many times their tools were reported useful for the query,
+// relative to the most reported match. Without feedback or with a zero weight, it's disabled.
+func (f *findFeedback) boost(query string, matches []ServerMatch, weight float64) rankingBoost {
+	if f == nil || weight <= 0 {
+		return rankingBoost{}
+	}
+
+	// Copy the counts, record() updates them concurrently
+	totals := map[string]int{}
+	maxTotal := 0
+	f.mu.Lock()
+	for _, match := range matches {
+		for _, count := range f.counts[normalizeFindQuery(query)][match.Name] {
+			totals[match.Name] += count
+		}
+		maxTotal = max(maxTotal, totals[match.Name])
+	}
+	f.mu.Unlock()
+
+	if maxTotal == 0 {
+		return rankingBoost{}
+	}
+
+	return rankingBoost{
+		weight: weight,
+		value: func(match ServerMatch) float64 {
+			return float64(totals[match.Name]) / float64(maxTotal)
+		},
+	}
+}
+
+// createRecordToolFeedbackTool implements a tool to report which tool was useful for an mcp-find query
+func (g *Gateway) createRecordToolFeedbackTool(configuration Configuration) *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "record-tool-feedback",
+		Description: "Report which tool was actually useful for an mcp-find query. The servers whose tools are reported most often for a query are ranked higher by the next mcp-find searches.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"query": {
+					Type:        "string",
+					Description: "Search query, as passed to mcp-find",
+				},
+				"tool": {
+					Type:        "string",
+					Description: "Name of the tool that was useful for the query",
+				},
+				"server": {
+					Type:        "string",
+					Description: "Name of the MCP server providing the tool. Optional for the tools currently exposed by the gateway",
+				},
+			},
+			Required: []string{"query", "tool"},
+		},
+	}
+
+	handler := func(_ context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		// Parse parameters
+		var params struct {
+			Query  string `json:"query"`
+			Tool   string `json:"tool"`
+			Server string `json:"server"`
+		}
+
+		if req.Params.Arguments == nil {
+			return nil, fmt.Errorf("missing arguments")
+		}
+
+		paramsBytes, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+		}
+
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if strings.TrimSpace(params.Query) == "" {
+			return nil, fmt.Errorf("query parameter is required")
+		}
+
+		toolName := strings.TrimSpace(params.Tool)
+		if toolName == "" {
+			return nil, fmt.Errorf("tool parameter is required")
+		}
+
+		serverName := strings.TrimSpace(params.Server)
+		if serverName == "" {
+			serverName = g.toolServerName(configuration, toolName)
+		}
+		if serverName == "" {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Can't find the server providing tool '%s', pass it with the server parameter.", toolName),
+				}},
+			}, nil
+		}
+		if _, found := configuration.servers[serverName]; !found {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Server '%s' not found in the catalog.", serverName),
+				}},
+			}, nil
+		}
+
+		count, err := g.findFeedback.record(params.Query, serverName, toolName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to persist the feedback: %w", err)
+		}
+
+		// The cached mcp-find responses don't account for the new feedback
+		g.clearFindCache()
+
+		return g.jsonToolResult(map[string]any{
+			"query":  params.Query,
+			"server": serverName,
+			"tool":   toolName,
+			"count":  count,
+		}, true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("record-tool-feedback", handler),
+	}
+}
+
+// toolServerName returns the server providing a tool exposed by the gateway or, failing that,
+// the only catalog server listing a tool with this name. It's empty if there's none.
+func (g *Gateway) toolServerName(configuration Configuration, toolName string) string {
+	g.capabilitiesMu.RLock()
+	toolReg, found := g.toolRegistrations[toolName]
+	g.capabilitiesMu.RUnlock()
+	if found && toolReg.ServerName != "" {
+		return toolReg.ServerName
+	}
+
+	var serverNames []string
+	for serverName, server := range configuration.servers {
+		if slices.ContainsFunc(server.Tools, func(tool catalog.Tool) bool { return tool.Name == toolName }) {
+			serverNames = append(serverNames, serverName)
+		}
+	}
+	if len(serverNames) != 1 {
+		return ""
+	}
+
+	return serverNames[0]
+}
@@ -0,0 +1,108 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestFindFeedbackPersisted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool-feedback.json")
+
+	feedback, err := newFindFeedback(path)
+	require.NoError(t, err)
+
+	count, err := feedback.record("GitHub  Issues", "github", "list_issues")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	count, err = feedback.record("github issues", "github", "list_issues")
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	count, err = feedback.record("github issues", "github", "create_issue")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	reloaded, err := newFindFeedback(path)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]map[string]map[string]int{
+		"github issues": {"github": {"list_issues": 2, "create_issue": 1}},
+	}, reloaded.counts)
+}
+
+func TestFindFeedbackInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tool-feedback.json")
+	require.NoError(t, os.WriteFile(path, []byte("not json"), 0o644))
+
+	_, err := newFindFeedback(path)
+	require.ErrorContains(t, err, "parsing tool feedback file")
+}
+
+func TestFindFeedbackBoost(t *testing.T) {
+	feedback := &findFeedback{counts: map[string]map[string]map[string]int{
+		"github issues": {"gitlab": {"list_issues": 2, "get_issue": 2}, "github": {"list_issues": 1}},
+	}}
+	matches := func() []ServerMatch {
+		return []ServerMatch{{Name: "github", Score: 50}, {Name: "gitlab", Score: 40}, {Name: "jira", Score: 30}}
+	}
+	rank := func(f *findFeedback, query string, weight float64) []string {
+		m := matches()
+		return matchNames(rankMatches(m, f.boost(query, m, weight)))
+	}
+
+	assert.Equal(t, []string{"github", "gitlab", "jira"}, rank(feedback, "github issues", 0))
+	assert.Equal(t, []string{"gitlab", "github", "jira"}, rank(feedback, "GitHub issues", 0.5))
+	assert.Equal(t, []string{"github", "gitlab", "jira"}, rank(feedback, "slack", 0.5))
+
+	var disabled *findFeedback
+	assert.Equal(t, []string{"github", "gitlab", "jira"}, rank(disabled, "github issues", 0.5))
+}
+
+func TestFindFeedbackConcurrentRecordAndBoost(t *testing.T) {
+	feedback, err := newFindFeedback(filepath.Join(t.TempDir(), "tool-feedback.json"))
+	require.NoError(t, err)
+	matches := []ServerMatch{{Name: "github", Score: 50}, {Name: "gitlab", Score: 40}}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _ = feedback.record("issues", "gitlab", "list_issues")
+		}()
+		go func() {
+			defer wg.Done()
+			boost := feedback.boost("issues", matches, 0.5)
+			if boost.value != nil {
+				_ = boost.value(matches[1])
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestToolServerName(t *testing.T) {
+	g := &Gateway{
+		toolRegistrations: map[string]ToolRegistration{
+			"gh:get_me": {ServerName: "github"},
+			"mcp-find":  {},
+		},
+	}
+	configuration := Configuration{
+		servers: map[string]catalog.Server{
+			"github": {Tools: []catalog.Tool{{Name: "get_me"}, {Name: "search"}}},
+			"brave":  {Tools: []catalog.Tool{{Name: "search"}, {Name: "summarize"}}},
+		},
+	}
+
+	assert.Equal(t, "github", g.toolServerName(configuration, "gh:get_me"))
+	assert.Equal(t, "brave", g.toolServerName(configuration, "summarize"))
+	// Ambiguous or unknown
+	assert.Empty(t, g.toolServerName(configuration, "search"))
+	assert.Empty(t, g.toolServerName(configuration, "mcp-find"))
+}
@@ -0,0 +1,134 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// findCache caches the mcp-find responses for a short time, keyed on the normalized parameters.
+// Each mcp-find tool gets its own cache, so it's invalidated when the configuration reloads.
+// The cache of the current mcp-find tool can also be flushed with the clear-caches tool.
+type findCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]findCacheEntry
+}
+
+type findCacheEntry struct {
+	response string
+	// matches are the servers of the response, logged again when it's served from the cache
+	matches []ServerMatch
+	expires time.Time
+}
+
+func newFindCache(ttl time.Duration) *findCache {
+	return &findCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[string]findCacheEntry{},
+	}
+}
+
+// normalizeFindQuery ignores the case and the spacing of an mcp-find query
+func normalizeFindQuery(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+func findCacheKey(query string, limit int, minScore float64, includeDisabled bool) string {
+	return fmt.Sprintf("%s|%d|%g|%t", normalizeFindQuery(query), limit, minScore, includeDisabled)
+}
+
+func (c *findCache) get(key string) (string, []ServerMatch, bool) {
+	if c.ttl <= 0 {
+		return "", nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, found := c.entries[key]
+	if !found {
+		return "", nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return "", nil, false
+	}
+
+	return entry.response, entry.matches, true
+}
+
+func (c *findCache) put(key, response string, matches []ServerMatch) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	c.entries[key] = findCacheEntry{
+		response: response,
+		matches:  matches,
+		expires:  now.Add(c.ttl),
+	}
+}
+
+// clear evicts all the entries and returns how many there were.
+func (c *findCache) clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	evicted := len(c.entries)
+	c.entries = map[string]findCacheEntry{}
+
+	return evicted
+}
+
+// clearFindCache evicts the responses cached by the current mcp-find tool, which are stale once
+// the enabled servers or the feedback change, and returns how many there were.
+func (g *Gateway) clearFindCache() int {
+	if cache := g.findCache.Load(); cache != nil {
+		return cache.clear()
+	}
+	return 0
+}
+
+// createClearCachesTool implements a tool that flushes the gateway's caches without a restart
+func (g *Gateway) createClearCachesTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "clear-caches",
+		Description: "Empty the gateway's caches, the cached mcp-find results and the cached results of the cacheable tools, so that the next calls see the latest catalog and tools. Returns how many entries were evicted from each cache.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+		},
+	}
+
+	handler := func(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		evicted := map[string]int{
+			"mcp-find":     g.clearFindCache(),
+			"tool-results": g.toolCache.clear(),
+		}
+
+		return g.jsonToolResult(evicted, true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("clear-caches", handler),
+	}
+}
@@ -0,0 +1,52 @@
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindCacheKey(t *testing.T) {
+	assert.Equal(t, findCacheKey("  GitHub   Issues ", 10, 0, false), findCacheKey("github issues", 10, 0, false))
+	assert.NotEqual(t, findCacheKey("github", 10, 0, false), findCacheKey("github", 5, 0, false))
+	assert.NotEqual(t, findCacheKey("github", 10, 0, false), findCacheKey("github", 10, 0.5, false))
+	assert.NotEqual(t, findCacheKey("github", 10, 0, false), findCacheKey("github", 10, 0, true))
+}
+
+func TestFindCacheExpires(t *testing.T) {
+	now := time.Now()
+	cache := newFindCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	cache.put("key", "response", nil)
+
+	response, _, found := cache.get("key")
+	assert.True(t, found)
+	assert.Equal(t, "response", response)
+
+	now = now.Add(time.Minute)
+	_, _, found = cache.get("key")
+	assert.False(t, found)
+}
+
+func TestFindCacheDisabled(t *testing.T) {
+	cache := newFindCache(0)
+
+	cache.put("key", "response", nil)
+
+	_, _, found := cache.get("key")
+	assert.False(t, found)
+}
+
+func TestFindCacheClear(t *testing.T) {
+	cache := newFindCache(time.Minute)
+	cache.put("github", "response", nil)
+	cache.put("slack", "response", nil)
+
+	assert.Equal(t, 2, cache.clear())
+
+	_, _, found := cache.get("github")
+	assert.False(t, found)
+	assert.Equal(t, 0, cache.clear())
+}
@@ -0,0 +1,109 @@
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+	"github.com/docker/mcp-gateway/pkg/secretsscan"
+)
+
+// findLogVersion is the version of the search log's format, bumped on incompatible changes.
+const findLogVersion = 1
+
+// redactedQuery replaces the queries, or the parts of queries, that hold a secret.
+const redactedQuery = "[REDACTED]"
+
+// findLogEntry is a line of the search log. See docs/feature-specs/dynamic_servers.md.
+type findLogEntry struct {
+	Version    int                `json:"version"`
+	Time       time.Time          `json:"time"`
+	Query      string             `json:"query"`
+	Redacted   bool               `json:"redacted"`
+	Limit      int                `json:"limit"`
+	MinScore   float64            `json:"min_score"`
+	Candidates []findLogCandidate `json:"candidates"`
+}
+
+type findLogCandidate struct {
+	Name  string `json:"name"`
+	Score int    `json:"score"`
+}
+
+// findLog appends each mcp-find search to a JSONL file, to build training data for re-rankers.
+// It's only enabled with --find-log.
+type findLog struct {
+	mu   sync.Mutex
+	file *os.File
+	now  func() time.Time
+}
+
+func openFindLog(path string) (*findLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening find log %s: %w", path, err)
+	}
+
+	return &findLog{
+		file: file,
+		now:  time.Now,
+	}, nil
+}
+
+// record logs a search and the candidates it returned, in order. Failing to log doesn't fail the search.
+func (l *findLog) record(query string, limit int, minScore float64, matches []ServerMatch, secrets map[string]string) {
+	if l == nil {
+		return
+	}
+
+	entry := findLogEntry{
+		Version:    findLogVersion,
+		Time:       l.now().UTC(),
+		Limit:      limit,
+		MinScore:   minScore,
+		Candidates: []findLogCandidate{},
+	}
+	entry.Query, entry.Redacted = redactQuery(query, secrets)
+	for _, match := range matches {
+		entry.Candidates = append(entry.Candidates, findLogCandidate{Name: match.Name, Score: match.Score})
+	}
+
+	buf, err := json.Marshal(entry)
+	if err != nil {
+		log.Warn(fmt.Sprintf("failed to log search: %v", err))
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(buf, '\n')); err != nil {
+		log.Warn(fmt.Sprintf("failed to log search: %v", err))
+	}
+}
+
+func (l *findLog) Close() error {
+	return l.file.Close()
+}
+
+// redactQuery removes the known secret values from a query. A query that still looks
+// like it holds a secret is entirely redacted.
+func redactQuery(query string, secrets map[string]string) (string, bool) {
+	redacted := false
+	for _, value := range secrets {
+		if value != "" && strings.Contains(query, value) {
+			query = strings.ReplaceAll(query, value, redactedQuery)
+			redacted = true
+		}
+	}
+
+	if secretsscan.ContainsSecrets(query) {
+		return redactedQuery, true
+	}
+
+	return query, redacted
+}
@@ -0,0 +1,149 @@
+package gateway
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// gzipMinSize is the size above which responses are compressed. Smaller payloads
+// aren't worth the overhead.
+const gzipMinSize = 1024
+
+// gzipHandler compresses the responses larger than gzipMinSize for the clients that accept
+// gzip. Event streams are never compressed so that events are delivered as they are flushed.
+func gzipHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !acceptsGzip(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		gw := &gzipResponseWriter{ResponseWriter: w}
+		defer gw.close()
+
+		next.ServeHTTP(gw, r)
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for encoding := range strings.SplitSeq(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+
+		// gzip;q=0 means the client refuses gzip
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err := strconv.ParseFloat(q, 64); err == nil && weight == 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+// gzipResponseWriter buffers the beginning of a response until it knows whether
+// it's big enough to be compressed.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	status      int
+	buf         []byte
+	gz          *gzip.Writer
+	passthrough bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(status int) {
+	if w.status != 0 || w.passthrough || w.gz != nil {
+		return
+	}
+	w.status = status
+
+	// Responses without a body and event streams are sent as is, right away
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified || w.isEventStream() {
+		w.commit()
+	}
+}
+
+func (w *gzipResponseWriter) Write(p []byte) (int, error) {
+	switch {
+	case w.passthrough:
+		return w.ResponseWriter.Write(p)
+	case w.gz != nil:
+		return w.gz.Write(p)
+	case w.isEventStream():
+		w.commit()
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.buf = append(w.buf, p...)
+	if len(w.buf) >= gzipMinSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+func (w *gzipResponseWriter) Flush() {
+	switch {
+	case w.gz != nil:
+		_ = w.gz.Flush()
+	case !w.passthrough:
+		// The handler wants the client to see what was written so far: stop buffering.
+		w.commit()
+	}
+
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *gzipResponseWriter) isEventStream() bool {
+	return strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream")
+}
+
+// commit sends the response uncompressed.
+func (w *gzipResponseWriter) commit() {
+	w.passthrough = true
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+	if len(w.buf) > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *gzipResponseWriter) startGzip() error {
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Add("Vary", "Accept-Encoding")
+	header.Del("Content-Length")
+	if w.status != 0 {
+		w.ResponseWriter.WriteHeader(w.status)
+	}
+
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	_, err := w.gz.Write(w.buf)
+	w.buf = nil
+	return err
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.gz != nil {
+		_ = w.gz.Close()
+		return
+	}
+	if !w.passthrough {
+		w.commit()
+	}
+}
@@ -0,0 +1,80 @@
+package gateway
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveGzip(t *testing.T, acceptEncoding string, handler http.HandlerFunc) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	rec := httptest.NewRecorder()
+	gzipHandler(handler).ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGzipHandlerCompressesLargeResponses(t *testing.T) {
+	body := strings.Repeat(`{"name":"tool"}`, 200)
+
+	rec := serveGzip(t, "gzip, deflate", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, body)
+	})
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "gzip", rec.Header().Get("Content-Encoding"))
+
+	reader, err := gzip.NewReader(rec.Body)
+	require.NoError(t, err)
+	uncompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(uncompressed))
+}
+
+func TestGzipHandlerKeepsSmallResponses(t *testing.T) {
+	rec := serveGzip(t, "gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, "ok", rec.Body.String())
+}
+
+func TestGzipHandlerKeepsEventStreams(t *testing.T) {
+	body := "data: " + strings.Repeat("x", 2*gzipMinSize) + "\n\n"
+
+	rec := serveGzip(t, "gzip", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, body)
+	})
+
+	assert.Empty(t, rec.Header().Get("Content-Encoding"))
+	assert.Equal(t, body, rec.Body.String())
+}
+
+func TestGzipHandlerWithoutAcceptEncoding(t *testing.T) {
+	body := strings.Repeat("x", 2*gzipMinSize)
+
+	for _, acceptEncoding := range []string{"", "deflate", "gzip;q=0"} {
+		rec := serveGzip(t, acceptEncoding, func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = io.WriteString(w, body)
+		})
+
+		assert.Empty(t, rec.Header().Get("Content-Encoding"), acceptEncoding)
+		assert.Equal(t, body, rec.Body.String(), acceptEncoding)
+	}
+}
@@ -60,7 +60,8 @@ func (g *Gateway) mcpToolHandler(tool catalog.Tool) mcp.ToolHandler {
 func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, annotations *mcp.ToolAnnotations) mcp.ToolHandler {
 	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Look up server configuration
-		serverConfig, _, ok := g.configuration.Find(serverName)
+		configuration := g.currentConfiguration()
+		serverConfig, _, ok := configuration.Find(serverName)
 		if !ok {
 			return nil, fmt.Errorf("server %q not found in configuration", serverName)
 		}
@@ -161,7 +162,8 @@ func (g *Gateway) mcpServerToolHandler(serverName string, server *mcp.Server, an
 func (g *Gateway) mcpServerPromptHandler(serverName string, server *mcp.Server) mcp.PromptHandler {
 	return func(ctx context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
 		// Look up server configuration
-		serverConfig, _, ok := g.configuration.Find(serverName)
+		configuration := g.currentConfiguration()
+		serverConfig, _, ok := configuration.Find(serverName)
 		if !ok {
 			return nil, fmt.Errorf("server %q not found in configuration", serverName)
 		}
@@ -227,7 +229,8 @@ func (g *Gateway) mcpServerPromptHandler(serverName string, server *mcp.Server)
 func (g *Gateway) mcpServerResourceHandler(serverName string, server *mcp.Server) mcp.ResourceHandler {
 	return func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
 		// Look up server configuration
-		serverConfig, _, ok := g.configuration.Find(serverName)
+		configuration := g.currentConfiguration()
+		serverConfig, _, ok := configuration.Find(serverName)
 		if !ok {
 			return nil, fmt.Errorf("server %q not found in configuration", serverName)
 		}
@@ -0,0 +1,82 @@
+package gateway
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// JSON formats of the internal tools' responses, see --json-format
+const (
+	JSONFormatIndented = "indented"
+	JSONFormatCompact  = "compact"
+)
+
+func validateJSONFormat(format string) error {
+	switch format {
+	case "", JSONFormatIndented, JSONFormatCompact:
+		return nil
+	default:
+		return fmt.Errorf("unsupported JSON format %q, expected %s or %s", format, JSONFormatIndented, JSONFormatCompact)
+	}
+}
+
+// marshalToolResponse encodes the response of an internal tool. Unless a format is forced
+// with --json-format, the responses meant to be read by humans (stats, diagnostics...) are
+// indented and the ones meant to be consumed by the model (search results...) are compact.
+func (g *Gateway) marshalToolResponse(v any, humanFacing bool) ([]byte, error) {
+	indent := humanFacing
+	switch g.JSONFormat {
+	case JSONFormatIndented:
+		indent = true
+	case JSONFormatCompact:
+		indent = false
+	}
+
+	var (
+		buf []byte
+		err error
+	)
+	if indent {
+		buf, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		buf, err = json.Marshal(v)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	return buf, nil
+}
+
+// jsonToolResult returns the JSON encoded response of an internal tool.
+func (g *Gateway) jsonToolResult(v any, humanFacing bool) (*mcp.CallToolResult, error) {
+	buf, err := g.marshalToolResponse(v, humanFacing)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.jsonResult(buf), nil
+}
+
+// jsonResult wraps an encoded response into a tool result. The JSON text is always returned,
+// for the clients that don't read structured content. With --structured-content, responses
+// that are JSON objects are also returned as structured content.
+func (g *Gateway) jsonResult(buf []byte) *mcp.CallToolResult {
+	result := &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: string(buf)}},
+	}
+	if g.StructuredContent && isJSONObject(buf) {
+		result.StructuredContent = json.RawMessage(buf)
+	}
+
+	return result
+}
+
+// isJSONObject tells whether an encoded value is an object, the only kind of structured content allowed by MCP.
+func isJSONObject(buf []byte) bool {
+	trimmed := bytes.TrimSpace(buf)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
@@ -0,0 +1,54 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalToolResponse(t *testing.T) {
+	response := map[string]int{"calls": 1}
+
+	tests := []struct {
+		format      string
+		humanFacing bool
+		expected    string
+	}{
+		{format: "", humanFacing: true, expected: "{\n  \"calls\": 1\n}"},
+		{format: "", humanFacing: false, expected: `{"calls":1}`},
+		{format: JSONFormatCompact, humanFacing: true, expected: `{"calls":1}`},
+		{format: JSONFormatIndented, humanFacing: false, expected: "{\n  \"calls\": 1\n}"},
+	}
+	for _, test := range tests {
+		g := &Gateway{Options: Options{JSONFormat: test.format}}
+
+		buf, err := g.marshalToolResponse(response, test.humanFacing)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, string(buf))
+	}
+}
+
+func TestValidateJSONFormat(t *testing.T) {
+	require.NoError(t, validateJSONFormat(""))
+	require.NoError(t, validateJSONFormat("compact"))
+	require.EqualError(t, validateJSONFormat("pretty"), `unsupported JSON format "pretty", expected indented or compact`)
+}
+
+func TestJSONResultStructuredContent(t *testing.T) {
+	g := &Gateway{}
+	result := g.jsonResult([]byte(`{"calls":1}`))
+	assert.Equal(t, `{"calls":1}`, result.Content[0].(*mcp.TextContent).Text)
+	assert.Nil(t, result.StructuredContent)
+
+	g.StructuredContent = true
+	result = g.jsonResult([]byte(`{"calls":1}`))
+	assert.Equal(t, `{"calls":1}`, result.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, json.RawMessage(`{"calls":1}`), result.StructuredContent)
+
+	// Only objects can be structured content
+	result = g.jsonResult([]byte(`[1,2]`))
+	assert.Nil(t, result.StructuredContent)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"os"
 	"slices"
@@ -14,6 +15,7 @@ import (
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 
+	"github.com/docker/mcp-gateway/pkg/catalog"
 	"github.com/docker/mcp-gateway/pkg/contextkeys"
 	"github.com/docker/mcp-gateway/pkg/desktop"
 	"github.com/docker/mcp-gateway/pkg/log"
@@ -25,7 +27,7 @@ import (
 func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration {
 	tool := &mcp.Tool{
 		Name:        "mcp-add",
-		Description: "Add a new MCP server to the session. The server must exist in the catalog.",
+		Description: "Add a new MCP server to the session. The server must exist in the catalog. Adding a bundle adds all its servers.",
 		InputSchema: &jsonschema.Schema{
 			Type: "object",
 			Properties: map[string]*jsonschema.Schema{
@@ -42,7 +44,8 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 		},
 	}
 
-	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var handler mcp.ToolHandler
+	handler = func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		// Parse parameters
 		var params struct {
 			Name     string `json:"name"`
@@ -67,22 +70,44 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 		}
 
 		serverName := strings.TrimSpace(params.Name)
+		configuration := g.currentConfiguration()
+
+		// Add the members of a bundle one by one, once they're all known to exist
+		if bundle := configuration.servers[serverName]; bundle.IsBundle() {
+			for _, member := range bundle.Members {
+				if nested := configuration.servers[member]; nested.IsBundle() {
+					return nil, fmt.Errorf("bundle %s can't contain the bundle %s", serverName, member)
+				}
+				if _, _, found := configuration.Find(member); !found {
+					return &mcp.CallToolResult{
+						Content: []mcp.Content{&mcp.TextContent{
+							Text: fmt.Sprintf("Error: Server '%s' of bundle '%s' not found in catalog.\n\nThe bundle was not added.", member, serverName),
+						}},
+						IsError: true,
+					}, nil
+				}
+			}
+
+			return g.addBundleMembers(ctx, req, serverName, bundle.Members, params.Activate, handler)
+		}
 
 		// Check if server exists in catalog
-		serverConfig, _, found := g.configuration.Find(serverName)
+		serverConfig, _, found := configuration.Find(serverName)
 		if !found {
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{&mcp.TextContent{
 					Text: fmt.Sprintf("Error: Server '%s' not found in catalog. Use mcp-find to search for available servers.", serverName),
 				}},
+				IsError: true,
 			}, nil
 		}
 
 		// Append the new server to the current serverNames if not already present
-		found = slices.Contains(g.configuration.serverNames, serverName)
+		found = slices.Contains(configuration.serverNames, serverName)
 		if !found {
-			g.configuration.serverNames = append(g.configuration.serverNames, serverName)
+			g.setServerNames(append(slices.Clone(configuration.serverNames), serverName))
 		}
+		configuration = g.currentConfiguration()
 
 		// Fetch updated secrets for the new server list
 		if g.configurator != nil {
@@ -92,12 +117,12 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 
 				// Use the same logic as readOnce - check SecretsPath
 				if fbc.SecretsPath == "docker-desktop" {
-					updatedSecrets, err = fbc.readDockerDesktopSecrets(ctx, g.configuration.servers, g.configuration.serverNames)
+					updatedSecrets, err = fbc.readDockerDesktopSecrets(ctx, configuration.servers, configuration.serverNames)
 				} else {
 					// Try each secrets path (colon-separated)
 					for secretPath := range strings.SplitSeq(fbc.SecretsPath, ":") {
 						if secretPath == "docker-desktop" {
-							updatedSecrets, err = fbc.readDockerDesktopSecrets(ctx, g.configuration.servers, g.configuration.serverNames)
+							updatedSecrets, err = fbc.readDockerDesktopSecrets(ctx, configuration.servers, configuration.serverNames)
 						} else {
 							updatedSecrets, err = fbc.readSecretsFromFile(ctx, secretPath)
 						}
@@ -108,7 +133,11 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 				}
 
 				if err == nil {
-					g.configuration.secrets = updatedSecrets
+					secrets := matchSecretNames(updatedSecrets, configuration.servers)
+					g.updateConfiguration(func(configuration *Configuration) {
+						configuration.secrets = secrets
+					})
+					configuration.secrets = secrets
 				} else {
 					log.Log("Warning: Failed to update secrets:", err)
 				}
@@ -119,7 +148,7 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 		var missingSecrets []string
 		if serverConfig != nil {
 			for _, secret := range serverConfig.Spec.Secrets {
-				if value, exists := g.configuration.secrets[secret.Name]; !exists || value == "" {
+				if value, exists := configuration.secrets[secret.Name]; !exists || value == "" {
 					missingSecrets = append(missingSecrets, secret.Name)
 				}
 			}
@@ -129,59 +158,7 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 		var missingConfig []string
 		if serverConfig != nil && len(serverConfig.Spec.Config) > 0 {
 			canonicalServerName := oci.CanonicalizeServerName(serverName)
-			serverConfigMap := g.configuration.config[canonicalServerName]
-
-			for _, configItem := range serverConfig.Spec.Config {
-				// Config items should be schema objects with a "name" property
-				schemaMap, ok := configItem.(map[string]any)
-				if !ok {
-					continue
-				}
-
-				// Get the name field - this identifies which config to validate
-				configName, ok := schemaMap["name"].(string)
-				if !ok || configName == "" {
-					continue
-				}
-
-				// Get the actual config value to validate
-				if serverConfigMap == nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (missing)", configName))
-					continue
-				}
-
-				configValue := serverConfigMap
-
-				// Convert the schema map to a jsonschema.Schema for validation
-				schemaBytes, err := json.Marshal(schemaMap)
-				if err != nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (invalid schema)", configName))
-					continue
-				}
-
-				var schema jsonschema.Schema
-				if err := json.Unmarshal(schemaBytes, &schema); err != nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (invalid schema)", configName))
-					continue
-				}
-
-				// Resolve the schema
-				resolved, err := schema.Resolve(nil)
-				if err != nil {
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (schema resolution failed)", configName))
-					continue
-				}
-
-				// Validate the config value against the schema
-				if err := resolved.Validate(configValue); err != nil {
-					// Extract a helpful error message
-					errMsg := err.Error()
-					if len(errMsg) > 100 {
-						errMsg = errMsg[:97] + "..."
-					}
-					missingConfig = append(missingConfig, fmt.Sprintf("%s (%s)", configName, errMsg))
-				}
-			}
+			missingConfig = validateConfigItems(serverConfig.Spec.ConfigItems(), configuration.config[canonicalServerName], configuration.configDefs)
 		}
 
 		// If secrets or config are missing, handle based on client type
@@ -220,6 +197,7 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 					Text: fmt.Sprintf("Error: Cannot add server '%s'. Missing required %s.\n\nThe server was not added. Please configure these first:%s",
 						serverName, strings.Join(missingItems, " and "), strings.Join(instructions, "\n")),
 				}},
+				IsError: true,
 			}, nil
 		}
 
@@ -232,6 +210,7 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 						Text: fmt.Sprintf("Error: Failed to pull image '%s' for server '%s'.\n\nDetails: %v\n\nThe server was not added. Please check the image name and your network connection.",
 							serverConfig.Spec.Image, serverName, err),
 					}},
+					IsError: true,
 				}, nil
 			}
 		}
@@ -264,9 +243,7 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 		}
 
 		// Persist configuration if session name is set
-		if err := g.configuration.Persist(); err != nil {
-			log.Log("Warning: Failed to persist configuration:", err)
-		}
+		g.persistConfiguration()
 
 		// Get the list of tools that were just added from this server
 		var addedTools []*mcp.Tool
@@ -288,14 +265,7 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 			// Create a tools list response matching the format from tools/list
 			toolsList := make([]map[string]any, 0, len(addedTools))
 			for _, tool := range addedTools {
-				toolMap := map[string]any{
-					"name":        tool.Name,
-					"description": tool.Description,
-				}
-				if tool.InputSchema != nil {
-					toolMap["inputSchema"] = tool.InputSchema
-				}
-				toolsList = append(toolsList, toolMap)
+				toolsList = append(toolsList, addedToolInfo(tool))
 			}
 
 			// Convert to JSON
@@ -333,6 +303,152 @@ func (g *Gateway) createMcpAddTool(clientConfig *clientConfig) *ToolRegistration
 	}
 }
 
+// validateConfigItems validates a server's config values against each of its config schemas.
+// It returns the config items that are missing or invalid, e.g. "github (missing)".
+//
+// Each schema is resolved within a combined document whose $defs hold the catalogs' shared
+// definitions and the server's config items by name, so that a schema can reference them
+// with "#/$defs/<name>". The schema's own $defs take precedence.
+func validateConfigItems(configItems []catalog.ConfigItem, values map[string]any, catalogDefs map[string]any) []string {
+	defs := map[string]any{}
+	maps.Copy(defs, catalogDefs)
+	for _, configItem := range configItems {
+		if configItem.Name != "" {
+			defs[configItem.Name] = configItem.Schema
+		}
+	}
+
+	var invalid []string
+	for _, configItem := range configItems {
+		// The name identifies which config to validate
+		configName := configItem.Name
+		if configName == "" {
+			continue
+		}
+
+		// Get the actual config value to validate
+		if values == nil {
+			invalid = append(invalid, fmt.Sprintf("%s (missing)", configName))
+			continue
+		}
+
+		// Build the combined schema document
+		document := maps.Clone(configItem.Schema)
+		documentDefs := maps.Clone(defs)
+		if ownDefs, ok := configItem.Schema["$defs"].(map[string]any); ok {
+			maps.Copy(documentDefs, ownDefs)
+		}
+		document["$defs"] = documentDefs
+
+		// Convert the schema map to a jsonschema.Schema for validation
+		schemaBytes, err := json.Marshal(document)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s (invalid schema)", configName))
+			continue
+		}
+
+		var schema jsonschema.Schema
+		if err := json.Unmarshal(schemaBytes, &schema); err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s (invalid schema)", configName))
+			continue
+		}
+
+		// Resolve the schema
+		resolved, err := schema.Resolve(nil)
+		if err != nil {
+			invalid = append(invalid, fmt.Sprintf("%s (schema resolution failed)", configName))
+			continue
+		}
+
+		// Validate the config value against the schema
+		if err := resolved.Validate(values); err != nil {
+			// Extract a helpful error message
+			errMsg := err.Error()
+			if len(errMsg) > 100 {
+				errMsg = errMsg[:97] + "..."
+			}
+			invalid = append(invalid, fmt.Sprintf("%s (%s)", configName, errMsg))
+		}
+	}
+
+	return invalid
+}
+
+// addBundleMembers adds the members of a bundle with the mcp-add handler. The result has the
+// result of each member, and is an error if one of them fails. The members added before it are
+// then removed, so that a bundle is either added as a whole or not at all.
+func (g *Gateway) addBundleMembers(ctx context.Context, req *mcp.CallToolRequest, bundleName string, members []string, activate bool, add mcp.ToolHandler) (*mcp.CallToolResult, error) {
+	enabledBefore := slices.Clone(g.currentConfiguration().serverNames)
+
+	result := &mcp.CallToolResult{}
+	for _, member := range members {
+		memberReq, err := bundleMemberRequest(req, member, activate)
+		if err != nil {
+			return nil, err
+		}
+
+		memberResult, err := add(ctx, memberReq)
+		if err == nil && !memberResult.IsError {
+			result.Content = append(result.Content, memberResult.Content...)
+			continue
+		}
+
+		removed := g.removeAddedServers(ctx, members, enabledBefore)
+		if err != nil {
+			return nil, fmt.Errorf("adding server %s of bundle %s: %w", member, bundleName, err)
+		}
+
+		result.Content = append(result.Content, memberResult.Content...)
+		result.Content = append(result.Content, &mcp.TextContent{
+			Text: fmt.Sprintf("Error: Bundle '%s' was not added because server '%s' failed. Servers removed again: %s.", bundleName, member, strings.Join(removed, ", ")),
+		})
+		result.IsError = true
+		return result, nil
+	}
+
+	return result, nil
+}
+
+// removeAddedServers removes the given servers that are enabled now but weren't before, and
+// returns their names.
+func (g *Gateway) removeAddedServers(ctx context.Context, serverNames []string, enabledBefore []string) []string {
+	var removed []string
+	for _, serverName := range serverNames {
+		if slices.Contains(enabledBefore, serverName) || !slices.Contains(g.currentConfiguration().serverNames, serverName) {
+			continue
+		}
+
+		if err := g.removeServer(ctx, serverName); err != nil {
+			log.Warn(fmt.Sprintf("failed to remove server %s: %v", serverName, err))
+		}
+		removed = append(removed, serverName)
+	}
+
+	g.persistConfiguration()
+
+	return removed
+}
+
+// bundleMemberRequest is a copy of an mcp-add request for a bundle, adding one of its members.
+func bundleMemberRequest(req *mcp.CallToolRequest, member string, activate bool) (*mcp.CallToolRequest, error) {
+	arguments, err := json.Marshal(map[string]any{
+		"name":     member,
+		"activate": activate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params := *req.Params
+	params.Arguments = arguments
+
+	return &mcp.CallToolRequest{
+		Session: req.Session,
+		Params:  &params,
+		Extra:   req.Extra,
+	}, nil
+}
+
 // shortenURL creates a shortened URL using Bitly's API
 // It returns the shortened URL or an error if the request fails
 func shortenURL(ctx context.Context, longURL string) (string, error) {
@@ -490,3 +606,20 @@ func (g *Gateway) getRemoteOAuthServerStatus(ctx context.Context, serverName str
 
 	return false, fmt.Sprintf("Successfully added server '%s'. You will need to authorize this server with: docker mcp oauth authorize %s", serverName, serverName)
 }
+
+// addedToolInfo describes a newly added tool the way tools/list does.
+// The annotations (read-only, destructive...) are only included when the server provides them.
+func addedToolInfo(tool *mcp.Tool) map[string]any {
+	toolMap := map[string]any{
+		"name":        tool.Name,
+		"description": tool.Description,
+	}
+	if tool.InputSchema != nil {
+		toolMap["inputSchema"] = tool.InputSchema
+	}
+	if tool.Annotations != nil {
+		toolMap["annotations"] = tool.Annotations
+	}
+
+	return toolMap
+}
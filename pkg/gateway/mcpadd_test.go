@@ -0,0 +1,139 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestBundleMemberRequest(t *testing.T) {
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{
+		Name:      "mcp-add",
+		Arguments: json.RawMessage(`{"name":"data-stack","activate":true}`),
+	}}
+
+	memberReq, err := bundleMemberRequest(req, "postgres", true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mcp-add", memberReq.Params.Name)
+	assert.JSONEq(t, `{"name":"postgres","activate":true}`, string(memberReq.Params.Arguments))
+	assert.JSONEq(t, `{"name":"data-stack","activate":true}`, string(req.Params.Arguments))
+}
+
+func TestValidateConfigItemsSharedDefs(t *testing.T) {
+	catalogDefs := map[string]any{
+		"port": map[string]any{"type": "integer", "minimum": 1, "maximum": 65535},
+	}
+	configItems := catalog.ParseConfigItems([]any{
+		map[string]any{
+			"name": "postgres",
+			"type": "object",
+			"properties": map[string]any{
+				"port": map[string]any{"$ref": "#/$defs/port"},
+			},
+			"required": []any{"port"},
+		},
+	})
+
+	assert.Empty(t, validateConfigItems(configItems, map[string]any{"port": 5432}, catalogDefs))
+
+	invalid := validateConfigItems(configItems, map[string]any{"port": 70000}, catalogDefs)
+	require.Len(t, invalid, 1)
+	assert.Contains(t, invalid[0], "postgres (")
+
+	assert.Equal(t, []string{"postgres (missing)"}, validateConfigItems(configItems, nil, catalogDefs))
+
+	// Without the catalog's definitions, the reference can't be resolved
+	assert.Equal(t, []string{"postgres (schema resolution failed)"}, validateConfigItems(configItems, map[string]any{"port": 5432}, nil))
+}
+
+func TestValidateConfigItemsCrossItemReference(t *testing.T) {
+	configItems := catalog.ParseConfigItems([]any{
+		map[string]any{
+			"name":       "credentials",
+			"type":       "object",
+			"properties": map[string]any{"user": map[string]any{"type": "string"}},
+		},
+		map[string]any{
+			"name": "database",
+			"type": "object",
+			"allOf": []any{
+				map[string]any{"$ref": "#/$defs/credentials"},
+			},
+		},
+	})
+
+	assert.Empty(t, validateConfigItems(configItems, map[string]any{"user": "admin"}, nil))
+	assert.Len(t, validateConfigItems(configItems, map[string]any{"user": 42}, nil), 2)
+}
+
+func TestAddedToolInfoAnnotations(t *testing.T) {
+	readOnly := &mcp.Tool{
+		Name:        "search",
+		Description: "Search the web",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}
+	buf, err := json.Marshal(addedToolInfo(readOnly))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"search","description":"Search the web","annotations":{"readOnlyHint":true}}`, string(buf))
+
+	plain := &mcp.Tool{Name: "fetch", Description: "Fetch a page"}
+	assert.NotContains(t, addedToolInfo(plain), "annotations")
+}
+
+func bundleTestGateway(t *testing.T, servers map[string]catalog.Server) *Gateway {
+	t.Helper()
+
+	g := &Gateway{
+		configuration: Configuration{
+			servers: servers,
+		},
+		toolRegistrations:           make(map[string]ToolRegistration),
+		serverCapabilities:          make(map[string]*ServerCapabilities),
+		serverAvailableCapabilities: make(map[string]*Capabilities),
+		mcpServer:                   mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil),
+	}
+	g.clientPool = newClientPool(Options{}, nil, g)
+
+	return g
+}
+
+func TestMcpAddBundleWithUnknownMember(t *testing.T) {
+	remote, _ := remoteTestServer(t, "search")
+	g := bundleTestGateway(t, map[string]catalog.Server{
+		"remote": remote,
+		"stack":  {Type: "bundle", Members: []string{"remote", "unknown"}},
+	})
+
+	result := callInternalTool(t, g.createMcpAddTool(nil), map[string]any{"name": "stack"})
+	require.True(t, result.IsError)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "Server 'unknown' of bundle 'stack' not found")
+
+	assert.Empty(t, g.currentConfiguration().serverNames)
+}
+
+func TestMcpAddBundleRollsBack(t *testing.T) {
+	remote, _ := remoteTestServer(t, "search")
+	secured, _ := remoteTestServer(t, "lookup")
+	secured.Secrets = []catalog.Secret{{Name: "secured.token", Env: "TOKEN"}}
+	g := bundleTestGateway(t, map[string]catalog.Server{
+		"remote":  remote,
+		"secured": secured,
+		"stack":   {Type: "bundle", Members: []string{"remote", "secured"}},
+	})
+
+	result := callInternalTool(t, g.createMcpAddTool(nil), map[string]any{"name": "stack"})
+	require.True(t, result.IsError)
+	require.Len(t, result.Content, 3)
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "Successfully added 1 tools in server 'remote'")
+	assert.Contains(t, result.Content[1].(*mcp.TextContent).Text, "Error: Cannot add server 'secured'")
+	assert.Contains(t, result.Content[2].(*mcp.TextContent).Text, "Servers removed again: remote, secured.")
+
+	assert.Empty(t, g.currentConfiguration().serverNames)
+	assert.NotContains(t, g.serverCapabilities, "remote")
+}
@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var memoryPattern = regexp.MustCompile(`^(?i)(\d+(?:\.\d+)?)([kmgt]?)(i?)(b?)$`)
+
+var memoryUnits = map[string]float64{
+	"":  1,
+	"k": 1 << 10,
+	"m": 1 << 20,
+	"g": 1 << 30,
+	"t": 1 << 40,
+}
+
+// parseMemory parses a memory limit such as "512Mb", "2GiB", "2g" or "1073741824"
+// into a number of bytes. Units are case-insensitive and, like docker, always binary.
+func parseMemory(value string) (int64, error) {
+	matches := memoryPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if matches == nil {
+		return 0, fmt.Errorf("invalid memory value %q: expected a number optionally followed by a unit (b, Kb, Mb, Gb, Tb, with or without 'i'), e.g. 2Gb", value)
+	}
+
+	unit := strings.ToLower(matches[2])
+	if unit == "" && matches[3] != "" {
+		return 0, fmt.Errorf("invalid memory value %q: 'i' must follow a unit (Ki, Mi, Gi, Ti)", value)
+	}
+
+	number, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid memory value %q: %w", value, err)
+	}
+
+	bytes := number * memoryUnits[unit]
+	if bytes > math.MaxInt64 {
+		return 0, fmt.Errorf("invalid memory value %q: value is too large", value)
+	}
+
+	return int64(bytes), nil
+}
+
+// normalizeMemory validates a memory limit and returns it in bytes, the form passed to docker.
+// An empty value means no limit and is returned unchanged.
+func normalizeMemory(value string) (string, error) {
+	if strings.TrimSpace(value) == "" {
+		return "", nil
+	}
+
+	bytes, err := parseMemory(value)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatInt(bytes, 10), nil
+}
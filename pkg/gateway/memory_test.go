@@ -0,0 +1,48 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMemory(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"", ""},
+		{"1073741824", "1073741824"},
+		{"512b", "512"},
+		{"2Gb", "2147483648"},
+		{"2gb", "2147483648"},
+		{"2GB", "2147483648"},
+		{"2g", "2147483648"},
+		{"2GiB", "2147483648"},
+		{"2Gi", "2147483648"},
+		{"512Mb", "536870912"},
+		{"512mib", "536870912"},
+		{"64Kb", "65536"},
+		{"1.5Gb", "1610612736"},
+		{" 1Tb ", "1099511627776"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			actual, err := normalizeMemory(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
+
+func TestNormalizeMemoryInvalid(t *testing.T) {
+	for _, input := range []string{"2 GB", "2gig", "Gb", "two gigs", "-1Gb", "2ib", "2Pb"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := normalizeMemory(input)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), input)
+		})
+	}
+}
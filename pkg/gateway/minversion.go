@@ -0,0 +1,60 @@
+package gateway
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+
+	"github.com/docker/mcp-gateway/cmd/docker-mcp/version"
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// gatewayVersion is the version of the running gateway, compared to the servers' minGatewayVersion.
+var gatewayVersion = version.Version
+
+// checkGatewayVersion returns an error if a server requires a newer gateway than the running one.
+// Development builds, whose version is not a semantic version, can run any server.
+func checkGatewayVersion(minVersion, current string) error {
+	if minVersion == "" {
+		return nil
+	}
+
+	minimum := canonicalVersion(minVersion)
+	if !semver.IsValid(minimum) {
+		return fmt.Errorf("invalid minGatewayVersion %q", minVersion)
+	}
+
+	running := canonicalVersion(current)
+	if !semver.IsValid(running) {
+		return nil
+	}
+
+	if semver.Compare(running, minimum) < 0 {
+		return fmt.Errorf("requires gateway %s or newer, this gateway is %s", minimum, running)
+	}
+
+	return nil
+}
+
+func canonicalVersion(v string) string {
+	if v != "" && !strings.HasPrefix(v, "v") {
+		return "v" + v
+	}
+	return v
+}
+
+// supportedServers drops, with a warning, the servers that require a newer gateway.
+func supportedServers(configuration Configuration, serverNames []string) []string {
+	var supported []string
+	for _, serverName := range serverNames {
+		if server, found := configuration.servers[serverName]; found {
+			if err := checkGatewayVersion(server.MinGatewayVersion, gatewayVersion); err != nil {
+				log.Warn(fmt.Sprintf("Skipping server %s: %v", serverName, err))
+				continue
+			}
+		}
+		supported = append(supported, serverName)
+	}
+	return supported
+}
@@ -0,0 +1,39 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestCheckGatewayVersion(t *testing.T) {
+	require.NoError(t, checkGatewayVersion("", "v0.20.0"))
+	require.NoError(t, checkGatewayVersion("v0.20.0", "v0.20.0"))
+	require.NoError(t, checkGatewayVersion("0.20.0", "v0.21.3"))
+	require.NoError(t, checkGatewayVersion("v0.30.0", "HEAD"))
+
+	err := checkGatewayVersion("v0.30.0", "v0.21.3")
+	require.EqualError(t, err, "requires gateway v0.30.0 or newer, this gateway is v0.21.3")
+
+	err = checkGatewayVersion("latest", "v0.21.3")
+	require.EqualError(t, err, `invalid minGatewayVersion "latest"`)
+}
+
+func TestSupportedServers(t *testing.T) {
+	previous := gatewayVersion
+	gatewayVersion = "v0.25.0"
+	t.Cleanup(func() { gatewayVersion = previous })
+
+	configuration := Configuration{
+		servers: map[string]catalog.Server{
+			"github": {MinGatewayVersion: "v0.20.0"},
+			"future": {MinGatewayVersion: "v1.0.0"},
+			"fetch":  {},
+		},
+	}
+
+	assert.Equal(t, []string{"github", "fetch"}, supportedServers(configuration, []string{"github", "future", "fetch"}))
+}
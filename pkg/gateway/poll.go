@@ -0,0 +1,102 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// pollTimeout bounds each check of a source, so that one that doesn't answer can't stall the polling.
+const pollTimeout = 30 * time.Second
+
+// urlPoller periodically checks http(s) configuration sources for changes.
+// It uses ETag and Last-Modified so that unchanged sources are cheap to check.
+type urlPoller struct {
+	client  *http.Client
+	sources map[string]*polledSource
+}
+
+type polledSource struct {
+	etag         string
+	lastModified string
+	content      []byte
+	known        bool
+}
+
+func newURLPoller(urls []string) *urlPoller {
+	sources := map[string]*polledSource{}
+	for _, url := range urls {
+		sources[url] = &polledSource{}
+	}
+
+	return &urlPoller{
+		client:  &http.Client{Timeout: pollTimeout},
+		sources: sources,
+	}
+}
+
+// poll checks every source and returns the new content of the ones that changed.
+// The first poll of a source only records its current state.
+func (p *urlPoller) poll(ctx context.Context) map[string][]byte {
+	changed := map[string][]byte{}
+
+	for url, source := range p.sources {
+		content, modified, err := p.check(ctx, url, source)
+		if err != nil {
+			log.Warn("failed to poll", url, ":", err)
+			continue
+		}
+		if modified {
+			changed[url] = content
+		}
+	}
+
+	return changed
+}
+
+func (p *urlPoller) check(ctx context.Context, url string, source *polledSource) ([]byte, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	if source.etag != "" {
+		req.Header.Set("If-None-Match", source.etag)
+	}
+	if source.lastModified != "" {
+		req.Header.Set("If-Modified-Since", source.lastModified)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNotModified:
+		return nil, false, nil
+	case http.StatusOK:
+	default:
+		return nil, false, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Servers that don't support conditional requests always return the content.
+	modified := source.known && !bytes.Equal(content, source.content)
+
+	source.etag = resp.Header.Get("ETag")
+	source.lastModified = resp.Header.Get("Last-Modified")
+	source.content = content
+	source.known = true
+
+	return content, modified, nil
+}
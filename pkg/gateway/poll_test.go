@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLPollerUsesETag(t *testing.T) {
+	content := "version: 1"
+	etag := `"1"`
+	var notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	poller := newURLPoller([]string{server.URL})
+
+	assert.Empty(t, poller.poll(context.Background()))
+	assert.Empty(t, poller.poll(context.Background()))
+	assert.Equal(t, 1, notModified)
+
+	content = "version: 2"
+	etag = `"2"`
+	assert.Equal(t, map[string][]byte{server.URL: []byte("version: 2")}, poller.poll(context.Background()))
+}
+
+func TestURLPollerWithoutConditionalRequests(t *testing.T) {
+	content := "version: 1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(content))
+	}))
+	defer server.Close()
+
+	poller := newURLPoller([]string{server.URL})
+
+	assert.Empty(t, poller.poll(context.Background()))
+	assert.Empty(t, poller.poll(context.Background()))
+
+	content = "version: 2"
+	assert.Len(t, poller.poll(context.Background()), 1)
+}
+
+func TestURLPollerIgnoresErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	poller := newURLPoller([]string{server.URL})
+
+	assert.Empty(t, poller.poll(context.Background()))
+}
+
+func TestURLPollerTimesOut(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	poller := newURLPoller([]string{server.URL})
+	poller.client.Timeout = 50 * time.Millisecond
+
+	done := make(chan map[string][]byte)
+	go func() { done <- poller.poll(context.Background()) }()
+
+	select {
+	case changed := <-done:
+		assert.Empty(t, changed)
+	case <-time.After(5 * time.Second):
+		t.Fatal("poll didn't time out")
+	}
+}
@@ -0,0 +1,123 @@
+package gateway
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+// popularityFields are the catalog metadata that mcp-find can blend with the relevance score
+var popularityFields = map[string]func(*catalog.Metadata) int{
+	"pulls":       func(m *catalog.Metadata) int { return m.Pulls },
+	"stars":       func(m *catalog.Metadata) int { return m.Stars },
+	"githubStars": func(m *catalog.Metadata) int { return m.GithubStars },
+}
+
+func validatePopularityRanking(field string, weight float64) error {
+	if weight < 0 || weight > 1 {
+		return fmt.Errorf("find rank weight must be between 0 and 1, got %g", weight)
+	}
+	if field == "" {
+		return nil
+	}
+	if _, found := popularityFields[field]; !found {
+		var supported []string
+		for name := range popularityFields {
+			supported = append(supported, name)
+		}
+		slices.Sort(supported)
+		return fmt.Errorf("unsupported find rank field %q, supported: %s", field, strings.Join(supported, ", "))
+	}
+	return nil
+}
+
+func popularity(server catalog.Server, field string) int {
+	value, found := popularityFields[field]
+	if !found || server.Metadata == nil {
+		return 0
+	}
+	return value(server.Metadata)
+}
+
+// rankingBoost is a signal blended with the relevance to rank the mcp-find results.
+// Its value is normalized between 0 and 1. A zero weight disables it.
+type rankingBoost struct {
+	weight float64
+	value  func(ServerMatch) float64
+}
+
+// popularityBoost boosts the matches on their popularity, relative to the most popular match.
+func popularityBoost(matches []ServerMatch, field string, weight float64) rankingBoost {
+	if field == "" || weight <= 0 {
+		return rankingBoost{}
+	}
+
+	maxPopularity := 0
+	for _, match := range matches {
+		maxPopularity = max(maxPopularity, popularity(match.Server, field))
+	}
+	if maxPopularity == 0 {
+		return rankingBoost{}
+	}
+
+	return rankingBoost{
+		weight: weight,
+		value: func(match ServerMatch) float64 {
+			return float64(popularity(match.Server, field)) / float64(maxPopularity)
+		},
+	}
+}
+
+// rankMatches orders the matches, already sorted by relevance, on a blend of their normalized
+// relevance and of the boosts, each one with its own weight. The scores are left unchanged so
+// that min_score still applies to the relevance only. Without boosts, the relevance order is kept.
+func rankMatches(matches []ServerMatch, boosts ...rankingBoost) []ServerMatch {
+	boosts = slices.DeleteFunc(boosts, func(boost rankingBoost) bool {
+		return boost.weight <= 0
+	})
+	if len(boosts) == 0 || len(matches) < 2 {
+		return matches
+	}
+
+	blended := map[string]float64{}
+	for _, match := range matches {
+		blended[match.Name] = blendedScore(match, boosts)
+	}
+
+	slices.SortStableFunc(matches, func(a, b ServerMatch) int {
+		// Higher blended scores first
+		scoreA, scoreB := blended[a.Name], blended[b.Name]
+		switch {
+		case scoreA > scoreB:
+			return -1
+		case scoreA < scoreB:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	return matches
+}
+
+// blendedScore is relevance*(1-Σweights) + Σ(weight*value) over the boosts. When the weights add up
+// to more than 1, they are scaled down to add up to 1 and the relevance only breaks the ties.
+func blendedScore(match ServerMatch, boosts []rankingBoost) float64 {
+	totalWeight := 0.0
+	for _, boost := range boosts {
+		totalWeight += boost.weight
+	}
+	scale := 1.0
+	if totalWeight > 1 {
+		scale = 1 / totalWeight
+		totalWeight = 1
+	}
+
+	score := (1 - totalWeight) * float64(match.Score) / maxMatchScore
+	for _, boost := range boosts {
+		score += scale * boost.weight * boost.value(match)
+	}
+	return score
+}
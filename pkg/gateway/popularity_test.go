@@ -0,0 +1,91 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func matchNames(matches []ServerMatch) []string {
+	var names []string
+	for _, match := range matches {
+		names = append(names, match.Name)
+	}
+	return names
+}
+
+func TestRankByPopularity(t *testing.T) {
+	newMatches := func() []ServerMatch {
+		return []ServerMatch{
+			{Name: "github", Score: 80, Server: catalog.Server{Metadata: &catalog.Metadata{Pulls: 100}}},
+			{Name: "gitlab", Score: 80, Server: catalog.Server{Metadata: &catalog.Metadata{Pulls: 1000}}},
+			{Name: "gitea", Score: 60},
+		}
+	}
+
+	rankByPopularity := func(field string, weight float64) []ServerMatch {
+		matches := newMatches()
+		return rankMatches(matches, popularityBoost(matches, field, weight))
+	}
+
+	// Pure relevance by default
+	assert.Equal(t, []string{"github", "gitlab", "gitea"}, matchNames(rankByPopularity("", 0.5)))
+	assert.Equal(t, []string{"github", "gitlab", "gitea"}, matchNames(rankByPopularity("pulls", 0)))
+
+	// Ties are broken by popularity, the scores are unchanged
+	matches := rankByPopularity("pulls", 0.1)
+	assert.Equal(t, []string{"gitlab", "github", "gitea"}, matchNames(matches))
+	assert.Equal(t, 80, matches[0].Score)
+
+	// No popularity data
+	assert.Equal(t, []string{"github", "gitlab", "gitea"}, matchNames(rankByPopularity("stars", 0.5)))
+}
+
+func TestRankMatchesCombinesBoosts(t *testing.T) {
+	matches := []ServerMatch{
+		{Name: "github", Score: 80, Server: catalog.Server{Metadata: &catalog.Metadata{Pulls: 1000}}},
+		{Name: "gitlab", Score: 80, Server: catalog.Server{Metadata: &catalog.Metadata{Pulls: 100}}},
+		{Name: "gitea", Score: 80},
+	}
+	feedback := &findFeedback{counts: map[string]map[string]map[string]int{
+		"git": {"gitea": {"list_repos": 3}},
+	}}
+
+	// The feedback applies on top of the popularity instead of discarding it
+	ranked := rankMatches(matches,
+		popularityBoost(matches, "pulls", 0.1),
+		feedback.boost("git", matches, 0.2))
+	assert.Equal(t, []string{"gitea", "github", "gitlab"}, matchNames(ranked))
+}
+
+func TestBlendedScoreWeights(t *testing.T) {
+	match := ServerMatch{Name: "github", Score: 50}
+	constant := func(weight, value float64) rankingBoost {
+		return rankingBoost{weight: weight, value: func(ServerMatch) float64 { return value }}
+	}
+
+	// Each boost keeps its own weight, the relevance gets the rest
+	assert.InDelta(t, 0.4*0.5+0.3*1, blendedScore(match, []rankingBoost{constant(0.3, 1), constant(0.3, 0)}), 1e-9)
+	assert.InDelta(t, 0.4*0.5+0.3*1, blendedScore(match, []rankingBoost{constant(0.3, 0), constant(0.3, 1)}), 1e-9)
+	assert.InDelta(t, 0.4*0.5+0.3*1+0.3*1, blendedScore(match, []rankingBoost{constant(0.3, 1), constant(0.3, 1)}), 1e-9)
+
+	// Weights above 1 in total are scaled down
+	assert.InDelta(t, 0.5, blendedScore(match, []rankingBoost{constant(0.8, 1), constant(0.8, 0)}), 1e-9)
+}
+
+func TestValidateFindFeedbackWeight(t *testing.T) {
+	require.NoError(t, validateFindFeedbackWeight(0.3, 0.3))
+	require.NoError(t, validateFindFeedbackWeight(0.5, 0.5))
+	require.EqualError(t, validateFindFeedbackWeight(1.5, 0), "find feedback weight must be between 0 and 1, got 1.5")
+	require.EqualError(t, validateFindFeedbackWeight(0.6, 0.5), "find feedback weight and find rank weight must add up to at most 1, got 0.6 and 0.5")
+}
+
+func TestValidatePopularityRanking(t *testing.T) {
+	require.NoError(t, validatePopularityRanking("", 0))
+	require.NoError(t, validatePopularityRanking("githubStars", 0.3))
+	require.EqualError(t, validatePopularityRanking("downloads", 0.3), `unsupported find rank field "downloads", supported: githubStars, pulls, stars`)
+	require.EqualError(t, validatePopularityRanking("pulls", 1.5), "find rank weight must be between 0 and 1, got 1.5")
+}
@@ -0,0 +1,176 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+type probedTool struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Annotations *mcp.ToolAnnotations `json:"annotations,omitempty"`
+}
+
+// createProbeServerTool implements a tool that starts a server, lists its tools live and stops it
+func (g *Gateway) createProbeServerTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "probe-server",
+		Description: "Check that an MCP server from the catalog starts, by launching it in a disposable container, listing the tools it really exposes and stopping it. Returns the live tools, how they differ from the catalog and the startup time.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+			Properties: map[string]*jsonschema.Schema{
+				"server": {
+					Type:        "string",
+					Description: "Name of the MCP server to probe",
+				},
+			},
+			Required: []string{"server"},
+		},
+	}
+
+	handler := func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if g.ReadOnlyConfig {
+			return nil, errReadOnlyConfig
+		}
+
+		// Parse parameters
+		var params struct {
+			Server string `json:"server"`
+		}
+
+		if req.Params.Arguments == nil {
+			return nil, fmt.Errorf("missing arguments")
+		}
+
+		paramsBytes, err := json.Marshal(req.Params.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal arguments: %w", err)
+		}
+
+		if err := json.Unmarshal(paramsBytes, &params); err != nil {
+			return nil, fmt.Errorf("failed to parse arguments: %w", err)
+		}
+
+		if params.Server == "" {
+			return nil, fmt.Errorf("server parameter is required")
+		}
+
+		serverName := strings.TrimSpace(params.Server)
+		configuration := g.currentConfiguration()
+		serverConfig, _, found := configuration.Find(serverName)
+		if !found {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Server '%s' not found in catalog. Use mcp-find to search for available servers.", serverName),
+				}},
+			}, nil
+		}
+		if serverConfig == nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Server '%s' is not an MCP server and can't be probed.", serverName),
+				}},
+			}, nil
+		}
+
+		// Pull the Docker image before trying to start the server
+		if serverConfig.Spec.Image != "" {
+			log.Log(fmt.Sprintf("Pulling image for server '%s': %s", serverName, serverConfig.Spec.Image))
+			if err := g.docker.PullImage(ctx, serverConfig.Spec.Image); err != nil {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{
+						Text: fmt.Sprintf("Error: Failed to pull image '%s' for server '%s': %v", serverConfig.Spec.Image, serverName, err),
+					}},
+				}, nil
+			}
+		}
+
+		// Cancelling the context stops the container, even if the server fails to initialize.
+		// The client is never shared with the tool calls, even for a long-lived server.
+		probeCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		start := time.Now()
+		client, err := g.clientPool.StartDisposableClient(probeCtx, serverConfig)
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Server '%s' failed to start after %s: %v", serverName, time.Since(start).Round(time.Millisecond), err),
+				}},
+			}, nil
+		}
+		defer client.Session().Close()
+		startupDuration := time.Since(start)
+
+		start = time.Now()
+		tools, err := client.Session().ListTools(probeCtx, &mcp.ListToolsParams{})
+		if err != nil {
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{
+					Text: fmt.Sprintf("Error: Server '%s' started but its tools couldn't be listed: %v", serverName, err),
+				}},
+			}, nil
+		}
+		listToolsDuration := time.Since(start)
+
+		var liveTools []probedTool
+		var liveToolNames []string
+		for _, tool := range tools.Tools {
+			liveTools = append(liveTools, probedTool{Name: tool.Name, Description: tool.Description, Annotations: tool.Annotations})
+			liveToolNames = append(liveToolNames, tool.Name)
+		}
+
+		var catalogToolNames []string
+		for _, tool := range configuration.servers[serverName].Tools {
+			catalogToolNames = append(catalogToolNames, tool.Name)
+		}
+
+		response := map[string]any{
+			"server":                 serverName,
+			"tools":                  liveTools,
+			"startup_duration_ms":    startupDuration.Milliseconds(),
+			"list_tools_duration_ms": listToolsDuration.Milliseconds(),
+		}
+		if len(catalogToolNames) > 0 {
+			missing, extra := compareToolNames(catalogToolNames, liveToolNames)
+			response["missing_from_server"] = missing
+			response["not_in_catalog"] = extra
+		}
+
+		return g.jsonToolResult(response, true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("probe-server", handler),
+	}
+}
+
+// compareToolNames returns the tools the catalog declares but the server doesn't expose,
+// and the tools the server exposes but the catalog doesn't declare.
+func compareToolNames(catalogTools, liveTools []string) ([]string, []string) {
+	missing := []string{}
+	for _, name := range catalogTools {
+		if !slices.Contains(liveTools, name) {
+			missing = append(missing, name)
+		}
+	}
+
+	extra := []string{}
+	for _, name := range liveTools {
+		if !slices.Contains(catalogTools, name) {
+			extra = append(extra, name)
+		}
+	}
+
+	return missing, extra
+}
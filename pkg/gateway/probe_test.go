@@ -0,0 +1,88 @@
+package gateway
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestCompareToolNames(t *testing.T) {
+	missing, extra := compareToolNames([]string{"get_me", "search_issues"}, []string{"get_me", "create_issue"})
+
+	assert.Equal(t, []string{"search_issues"}, missing)
+	assert.Equal(t, []string{"create_issue"}, extra)
+}
+
+func TestCompareToolNamesIdentical(t *testing.T) {
+	missing, extra := compareToolNames([]string{"fetch"}, []string{"fetch"})
+
+	assert.Empty(t, missing)
+	assert.Empty(t, extra)
+}
+
+func TestProbeServerUsesDisposableClient(t *testing.T) {
+	remote, server := remoteTestServer(t, "get_me", "search_issues")
+	remote.LongLived = true
+	remote.Tools = []catalog.Tool{{Name: "get_me"}, {Name: "create_issue"}}
+
+	g := &Gateway{
+		Options: Options{LongLived: true},
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers:     map[string]catalog.Server{"github": remote},
+		},
+	}
+	g.clientPool = newClientPool(g.Options, nil, g)
+
+	result := callInternalTool(t, g.createProbeServerTool(), map[string]any{"server": "github"})
+	require.False(t, result.IsError)
+
+	var response struct {
+		Tools             []probedTool `json:"tools"`
+		MissingFromServer []string     `json:"missing_from_server"`
+		NotInCatalog      []string     `json:"not_in_catalog"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+	assert.Len(t, response.Tools, 2)
+	assert.Equal(t, []string{"create_issue"}, response.MissingFromServer)
+	assert.Equal(t, []string{"search_issues"}, response.NotInCatalog)
+
+	// The server was stopped, not kept for the next tool calls
+	assert.Empty(t, g.clientPool.keptClients)
+	assert.Eventually(t, func() bool {
+		for range server.Sessions() {
+			return false
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestProbeServerReadOnly(t *testing.T) {
+	g := &Gateway{
+		Options: Options{ReadOnlyConfig: true},
+		configuration: Configuration{
+			serverNames: []string{"github"},
+			servers:     map[string]catalog.Server{"github": {Image: "mcp/github"}},
+		},
+	}
+
+	_, err := callInternalToolErr(t, g.createProbeServerTool(), map[string]any{"server": "github"})
+	require.ErrorContains(t, err, errReadOnlyConfig.Error())
+}
+
+func TestProbeServerReadsCurrentConfiguration(t *testing.T) {
+	g := &Gateway{configuration: Configuration{servers: map[string]catalog.Server{}}}
+	probe := g.createProbeServerTool()
+
+	// A server added after the tool was created is found
+	g.setConfiguration(Configuration{servers: map[string]catalog.Server{"fetch": {Type: "poci"}}})
+
+	result := callInternalTool(t, probe, map[string]any{"server": "fetch"})
+	assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, "is not an MCP server")
+}
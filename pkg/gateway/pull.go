@@ -2,10 +2,19 @@ package gateway
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
 	"github.com/docker/mcp-gateway/pkg/log"
 	"github.com/docker/mcp-gateway/pkg/signatures"
 )
@@ -30,6 +39,10 @@ func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration
 		return err
 	}
 
+	if err := g.verifyImageDigests(ctx, dockerImages); err != nil {
+		return err
+	}
+
 	if err := g.verifyImages(ctx, verifiableImages); err != nil {
 		return err
 	}
@@ -39,15 +52,165 @@ func (g *Gateway) pullAndVerify(ctx context.Context, configuration Configuration
 
 func (g *Gateway) pullImages(ctx context.Context, images []string) error {
 	start := time.Now()
+	progress := &pullProgress{total: len(images)}
 
-	if err := g.docker.PullImages(ctx, images...); err != nil {
+	pullCtx := ctx
+	if g.PullTimeout > 0 {
+		var cancel context.CancelFunc
+		pullCtx, cancel = context.WithTimeout(ctx, g.PullTimeout)
+		defer cancel()
+	}
+	// Look the registry auth up once for all the images
+	pullCtx = docker.WithSharedRegistryAuth(pullCtx)
+
+	var err error
+	if g.FailFastPull {
+		err = g.pullImagesFailFast(pullCtx, images, progress)
+	} else {
+		err = g.pullAllImages(pullCtx, images, progress)
+	}
+	if err != nil {
+		// Only report the timeout, not the pulls it cancelled
+		if ctx.Err() == nil && errors.Is(pullCtx.Err(), context.DeadlineExceeded) {
+			return newPullTimeoutError(g.PullTimeout, images, progress)
+		}
 		return fmt.Errorf("pulling docker images: %w", err)
 	}
 
-	log.Log("> Images pulled in", time.Since(start))
+	log.Log("> Images pulled in", time.Since(start), fmt.Sprintf("(%d new, %d already present)", progress.pulled.Load(), progress.present.Load()))
 	return nil
 }
 
+// pullTimeoutError is returned when the pulls take longer than --pull-timeout.
+type pullTimeoutError struct {
+	Timeout   time.Duration
+	Completed []string
+	Cancelled []string
+}
+
+func newPullTimeoutError(timeout time.Duration, images []string, progress *pullProgress) *pullTimeoutError {
+	completed := progress.completedImages()
+
+	var cancelled []string
+	for _, image := range images {
+		if !slices.Contains(completed, image) {
+			cancelled = append(cancelled, image)
+		}
+	}
+
+	return &pullTimeoutError{
+		Timeout:   timeout,
+		Completed: completed,
+		Cancelled: cancelled,
+	}
+}
+
+func (e *pullTimeoutError) Error() string {
+	return fmt.Sprintf("pulling docker images: timed out after %s, %d/%d image(s) pulled, cancelled: %s",
+		e.Timeout, len(e.Completed), len(e.Completed)+len(e.Cancelled), strings.Join(e.Cancelled, ", "))
+}
+
+// pullProgress reports the progress of the pulls, image by image.
+type pullProgress struct {
+	total   int
+	done    atomic.Int32
+	pulled  atomic.Int32
+	present atomic.Int32
+
+	mu        sync.Mutex
+	completed []string
+}
+
+func (p *pullProgress) report(image string, alreadyPresent bool, err error) {
+	done := p.done.Add(1)
+	if err == nil {
+		p.mu.Lock()
+		p.completed = append(p.completed, image)
+		p.mu.Unlock()
+	}
+
+	var status string
+	switch {
+	case err != nil:
+		status = "couldn't be pulled"
+	case alreadyPresent:
+		p.present.Add(1)
+		status = "pulled (already present)"
+	default:
+		p.pulled.Add(1)
+		status = "pulled (new)"
+	}
+
+	log.Log(fmt.Sprintf("  > [%d/%d] %s %s", done, p.total, imageBaseName(image), status))
+}
+
+// completedImages lists the images pulled successfully so far, sorted.
+func (p *pullProgress) completedImages() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	completed := slices.Clone(p.completed)
+	slices.Sort(completed)
+	return completed
+}
+
+// pullImage pulls an image, reporting whether it was already present.
+// Nothing is pulled once the context is done.
+func (g *Gateway) pullImage(ctx context.Context, image string, progress *pullProgress) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err := g.docker.InspectImage(ctx, image)
+	alreadyPresent := err == nil
+
+	err = g.docker.PullImage(ctx, image)
+	progress.report(image, alreadyPresent, err)
+	return err
+}
+
+// pullImagesFailFast stops at the first image that can't be pulled.
+func (g *Gateway) pullImagesFailFast(ctx context.Context, images []string, progress *pullProgress) error {
+	errs, ctx := errgroup.WithContext(ctx)
+	errs.SetLimit(g.maxConcurrentLaunches())
+	for _, image := range images {
+		errs.Go(func() error {
+			return g.pullImage(ctx, image, progress)
+		})
+	}
+
+	return errs.Wait()
+}
+
+// pullAllImages tries to pull every image and reports all the ones that couldn't be pulled.
+func (g *Gateway) pullAllImages(ctx context.Context, images []string, progress *pullProgress) error {
+	var (
+		lock   sync.Mutex
+		failed []string
+	)
+
+	var errs errgroup.Group
+	errs.SetLimit(g.maxConcurrentLaunches())
+	for _, image := range images {
+		errs.Go(func() error {
+			if err := g.pullImage(ctx, image, progress); err != nil {
+				lock.Lock()
+				failed = append(failed, fmt.Sprintf("  - %s: %s", image, err))
+				lock.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = errs.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	sort.Strings(failed)
+	return fmt.Errorf("%d image(s) couldn't be pulled:\n%s", len(failed), strings.Join(failed, "\n"))
+}
+
 func (g *Gateway) verifyImages(ctx context.Context, images []string) error {
 	if !g.VerifySignatures {
 		return nil
@@ -64,6 +227,43 @@ func (g *Gateway) verifyImages(ctx context.Context, images []string) error {
 	return nil
 }
 
+// verifyImageDigests checks that the images pinned by digest in the catalog
+// match the digest of the pulled images.
+func (g *Gateway) verifyImageDigests(ctx context.Context, images []string) error {
+	for _, image := range images {
+		_, expected, found := strings.Cut(image, "@")
+		if !found {
+			continue
+		}
+
+		inspect, err := g.docker.InspectImage(ctx, image)
+		if err != nil {
+			return fmt.Errorf("inspecting docker image %s: %w", image, err)
+		}
+
+		matched := false
+		var digests []string
+		for _, repoDigest := range inspect.RepoDigests {
+			_, digest, _ := strings.Cut(repoDigest, "@")
+			if digest == expected {
+				matched = true
+				break
+			}
+			digests = append(digests, digest)
+		}
+
+		if !matched {
+			actual := "none"
+			if len(digests) > 0 {
+				actual = strings.Join(digests, ", ")
+			}
+			return fmt.Errorf("digest mismatch for docker image %s: expected %s, got %s", imageBaseName(image), expected, actual)
+		}
+	}
+
+	return nil
+}
+
 func imageBaseNames(names []string) []string {
 	baseNames := make([]string, len(names))
 
@@ -82,3 +282,16 @@ func imageBaseName(name string) string {
 
 	return name
 }
+
+func (g *Gateway) maxConcurrentLaunches() int {
+	return maxConcurrentLaunches(g.Options)
+}
+
+// maxConcurrentLaunches returns how many images can be pulled, or containers started,
+// at the same time. Defaults to GOMAXPROCS.
+func maxConcurrentLaunches(options Options) int {
+	if options.MaxConcurrentLaunches > 0 {
+		return options.MaxConcurrentLaunches
+	}
+	return runtime.GOMAXPROCS(0)
+}
@@ -0,0 +1,158 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+type fakePullClient struct {
+	docker.Client
+	pulled      atomic.Int32
+	missing     map[string]bool
+	repoDigests map[string][]string
+	present     map[string]bool
+}
+
+func (c *fakePullClient) InspectImage(_ context.Context, name string) (image.InspectResponse, error) {
+	if !c.present[name] && c.repoDigests[name] == nil {
+		return image.InspectResponse{}, errors.New("no such image")
+	}
+	return image.InspectResponse{RepoDigests: c.repoDigests[name]}, nil
+}
+
+func (c *fakePullClient) PullImage(_ context.Context, name string) error {
+	if c.missing[name] {
+		return errors.New("not found")
+	}
+	c.pulled.Add(1)
+	return nil
+}
+
+func TestPullImagesReportsAllFailures(t *testing.T) {
+	client := &fakePullClient{missing: map[string]bool{"mcp/missing": true, "mcp/unknown": true}}
+	g := &Gateway{docker: client}
+
+	err := g.pullImages(t.Context(), []string{"mcp/duckduckgo", "mcp/missing", "mcp/fetch", "mcp/unknown"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "2 image(s) couldn't be pulled")
+	assert.Contains(t, err.Error(), "mcp/missing: not found")
+	assert.Contains(t, err.Error(), "mcp/unknown: not found")
+	assert.Equal(t, int32(2), client.pulled.Load())
+}
+
+func TestPullImagesFailFast(t *testing.T) {
+	client := &fakePullClient{missing: map[string]bool{"mcp/missing": true}}
+	g := &Gateway{docker: client}
+	g.FailFastPull = true
+	g.MaxConcurrentLaunches = 1
+
+	err := g.pullImages(t.Context(), []string{"mcp/missing", "mcp/duckduckgo"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+	assert.NotContains(t, err.Error(), "couldn't be pulled")
+}
+
+func TestPullImages(t *testing.T) {
+	client := &fakePullClient{}
+	g := &Gateway{docker: client}
+
+	err := g.pullImages(t.Context(), []string{"mcp/duckduckgo", "mcp/fetch"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), client.pulled.Load())
+}
+
+func TestPullImagesProgress(t *testing.T) {
+	client := &fakePullClient{
+		missing: map[string]bool{"mcp/missing": true},
+		present: map[string]bool{"mcp/fetch": true},
+	}
+	g := &Gateway{docker: client}
+	progress := &pullProgress{total: 3}
+
+	err := g.pullAllImages(t.Context(), []string{"mcp/duckduckgo", "mcp/missing", "mcp/fetch"}, progress)
+	require.Error(t, err)
+
+	assert.Equal(t, int32(3), progress.done.Load())
+	assert.Equal(t, int32(1), progress.pulled.Load())
+	assert.Equal(t, int32(1), progress.present.Load())
+}
+
+// slowPullClient never finishes pulling the slow images, until the context is done
+type slowPullClient struct {
+	fakePullClient
+	slow map[string]bool
+}
+
+func (c *slowPullClient) PullImage(ctx context.Context, name string) error {
+	if c.slow[name] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return c.fakePullClient.PullImage(ctx, name)
+}
+
+func TestPullImagesTimeout(t *testing.T) {
+	client := &slowPullClient{slow: map[string]bool{"mcp/slow": true}}
+	g := &Gateway{docker: client}
+	g.PullTimeout = 50 * time.Millisecond
+	g.MaxConcurrentLaunches = 1
+
+	err := g.pullImages(t.Context(), []string{"mcp/duckduckgo", "mcp/slow", "mcp/fetch"})
+	require.Error(t, err)
+
+	var timeoutErr *pullTimeoutError
+	require.ErrorAs(t, err, &timeoutErr)
+	assert.Equal(t, []string{"mcp/duckduckgo"}, timeoutErr.Completed)
+	assert.Equal(t, []string{"mcp/slow", "mcp/fetch"}, timeoutErr.Cancelled)
+	assert.Equal(t, "pulling docker images: timed out after 50ms, 1/3 image(s) pulled, cancelled: mcp/slow, mcp/fetch", err.Error())
+	assert.Equal(t, int32(1), client.pulled.Load())
+}
+
+func TestPullImagesWithinTimeout(t *testing.T) {
+	client := &fakePullClient{}
+	g := &Gateway{docker: client}
+	g.PullTimeout = time.Minute
+
+	err := g.pullImages(t.Context(), []string{"mcp/duckduckgo", "mcp/fetch"})
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), client.pulled.Load())
+}
+
+func TestVerifyImageDigests(t *testing.T) {
+	pinned := "mcp/fetch@sha256:1111"
+	client := &fakePullClient{repoDigests: map[string][]string{
+		pinned: {"mcp/fetch@sha256:1111"},
+	}}
+	g := &Gateway{docker: client}
+
+	require.NoError(t, g.verifyImageDigests(t.Context(), []string{pinned, "mcp/duckduckgo"}))
+}
+
+func TestVerifyImageDigestsMismatch(t *testing.T) {
+	pinned := "mcp/fetch@sha256:1111"
+	client := &fakePullClient{repoDigests: map[string][]string{
+		pinned: {"mcp/fetch@sha256:2222"},
+	}}
+	g := &Gateway{docker: client}
+
+	err := g.verifyImageDigests(t.Context(), []string{pinned})
+	require.Error(t, err)
+	assert.Equal(t, "digest mismatch for docker image mcp/fetch: expected sha256:1111, got sha256:2222", err.Error())
+}
+
+func TestVerifyImageDigestsNoRepoDigest(t *testing.T) {
+	client := &fakePullClient{present: map[string]bool{"mcp/fetch@sha256:1111": true}}
+	g := &Gateway{docker: client}
+
+	err := g.verifyImageDigests(t.Context(), []string{"mcp/fetch@sha256:1111"})
+	require.ErrorContains(t, err, "expected sha256:1111, got none")
+}
@@ -0,0 +1,238 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/log"
+)
+
+// quotasFile is where the quota counters are persisted, relative to ~/.docker/mcp/
+const quotasFile = "quotas.json"
+
+// toolQuota limits the number of calls to a tool within a window of time
+type toolQuota struct {
+	Limit  int
+	Window time.Duration
+}
+
+// quotaUsage counts the calls to a tool in the current window
+type quotaUsage struct {
+	WindowStart time.Time `json:"window_start"`
+	Calls       int       `json:"calls"`
+}
+
+type quotaStatus struct {
+	Tool      string    `json:"tool"`
+	Limit     int       `json:"limit"`
+	Window    string    `json:"window"`
+	Used      int       `json:"used"`
+	Remaining int       `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
+
+// quotaTracker enforces per-tool quotas. Counters are persisted so that they survive restarts.
+type quotaTracker struct {
+	quotas map[string]toolQuota
+	path   string
+	now    func() time.Time
+
+	mu    sync.Mutex
+	usage map[string]*quotaUsage
+}
+
+// parseToolQuotas parses quotas in the `tool=limit/window` format, e.g. `search=100/24h`
+func parseToolQuotas(values []string) (map[string]toolQuota, error) {
+	quotas := map[string]toolQuota{}
+
+	for _, value := range values {
+		toolName, spec, ok := strings.Cut(value, "=")
+		if !ok || toolName == "" {
+			return nil, fmt.Errorf("invalid tool quota %q, expected tool=limit/window", value)
+		}
+
+		limitValue, windowValue, ok := strings.Cut(spec, "/")
+		if !ok {
+			return nil, fmt.Errorf("invalid tool quota %q, expected tool=limit/window", value)
+		}
+
+		limit, err := strconv.Atoi(limitValue)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit in tool quota %q", value)
+		}
+
+		window, err := time.ParseDuration(windowValue)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid window in tool quota %q", value)
+		}
+
+		quotas[toolName] = toolQuota{Limit: limit, Window: window}
+	}
+
+	return quotas, nil
+}
+
+// loadToolQuotas parses the quotas and loads their counters from ~/.docker/mcp/, or from the internal tools directory
+func loadToolQuotas(values []string, sandboxDir string) (*quotaTracker, error) {
+	quotas, err := parseToolQuotas(values)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := internalToolPath(sandboxDir, quotasFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQuotaTracker(quotas, path)
+}
+
+// newQuotaTracker loads the persisted counters, if any.
+func newQuotaTracker(quotas map[string]toolQuota, path string) (*quotaTracker, error) {
+	tracker := &quotaTracker{
+		quotas: quotas,
+		path:   path,
+		now:    time.Now,
+		usage:  map[string]*quotaUsage{},
+	}
+
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tracker, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(buf, &tracker.usage); err != nil {
+		return nil, fmt.Errorf("parsing quotas file %s: %w", path, err)
+	}
+
+	return tracker, nil
+}
+
+// consume counts a call to a tool, failing if its quota is exhausted.
+func (t *quotaTracker) consume(toolName string) error {
+	quota, found := t.quotas[toolName]
+	if !found {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := t.currentUsage(toolName, quota)
+	if usage.Calls >= quota.Limit {
+		return fmt.Errorf("quota exceeded for tool %q: %d calls per %s, resets at %s",
+			toolName, quota.Limit, quota.Window, usage.WindowStart.Add(quota.Window).Format(time.RFC3339))
+	}
+	usage.Calls++
+
+	if err := t.persist(); err != nil {
+		log.Warn("failed to persist tool quotas:", err)
+	}
+
+	return nil
+}
+
+// currentUsage returns the usage of the current window, starting a new window when the previous one is over.
+func (t *quotaTracker) currentUsage(toolName string, quota toolQuota) *quotaUsage {
+	now := t.now()
+
+	usage, found := t.usage[toolName]
+	if !found || !now.Before(usage.WindowStart.Add(quota.Window)) {
+		usage = &quotaUsage{WindowStart: now}
+		t.usage[toolName] = usage
+	}
+
+	return usage
+}
+
+func (t *quotaTracker) persist() error {
+	buf, err := json.MarshalIndent(t.usage, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(t.path, buf, 0o644)
+}
+
+func (t *quotaTracker) status() []quotaStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	statuses := []quotaStatus{}
+	for toolName, quota := range t.quotas {
+		usage := t.currentUsage(toolName, quota)
+		statuses = append(statuses, quotaStatus{
+			Tool:      toolName,
+			Limit:     quota.Limit,
+			Window:    quota.Window.String(),
+			Used:      usage.Calls,
+			Remaining: max(quota.Limit-usage.Calls, 0),
+			ResetsAt:  usage.WindowStart.Add(quota.Window),
+		})
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Tool < statuses[j].Tool
+	})
+
+	return statuses
+}
+
+// withToolQuota refuses the calls to a tool once its quota is exhausted.
+func (t *quotaTracker) withToolQuota(toolName string, handler mcp.ToolHandler) mcp.ToolHandler {
+	if t == nil {
+		return handler
+	}
+	if _, found := t.quotas[toolName]; !found {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if err := t.consume(toolName); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// createQuotaStatusTool implements a tool reporting the remaining quota of each tool
+func (g *Gateway) createQuotaStatusTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "quota-status",
+		Description: "Report the quota of the tools that have one: how many calls are allowed per window, how many were used, how many remain and when the window resets.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+		},
+	}
+
+	handler := func(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		statuses := []quotaStatus{}
+		if g.quotas != nil {
+			statuses = g.quotas.status()
+		}
+
+		return g.jsonToolResult(statuses, true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("quota-status", handler),
+	}
+}
@@ -0,0 +1,78 @@
+package gateway
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolQuotas(t *testing.T) {
+	quotas, err := parseToolQuotas([]string{"search=100/24h", "fetch=5/1m"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]toolQuota{
+		"search": {Limit: 100, Window: 24 * time.Hour},
+		"fetch":  {Limit: 5, Window: time.Minute},
+	}, quotas)
+
+	for _, invalid := range []string{"search", "=1/1h", "search=100", "search=x/1h", "search=-1/1h", "search=1/x", "search=1/0s"} {
+		_, err := parseToolQuotas([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestQuotaTracker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "quotas.json")
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tracker, err := newQuotaTracker(map[string]toolQuota{"search": {Limit: 2, Window: time.Hour}}, path)
+	require.NoError(t, err)
+	tracker.now = func() time.Time { return now }
+
+	require.NoError(t, tracker.consume("search"))
+	require.NoError(t, tracker.consume("search"))
+	require.ErrorContains(t, tracker.consume("search"), `quota exceeded for tool "search": 2 calls per 1h0m0s`)
+	require.NoError(t, tracker.consume("fetch"))
+
+	// Counters survive restarts
+	restarted, err := newQuotaTracker(map[string]toolQuota{"search": {Limit: 2, Window: time.Hour}}, path)
+	require.NoError(t, err)
+	restarted.now = func() time.Time { return now.Add(30 * time.Minute) }
+	assert.Equal(t, []quotaStatus{{
+		Tool:      "search",
+		Limit:     2,
+		Window:    "1h0m0s",
+		Used:      2,
+		Remaining: 0,
+		ResetsAt:  now.Add(time.Hour),
+	}}, restarted.status())
+
+	// A new window starts once the previous one is over
+	restarted.now = func() time.Time { return now.Add(time.Hour) }
+	require.NoError(t, restarted.consume("search"))
+	assert.Equal(t, 1, restarted.status()[0].Remaining)
+}
+
+func TestWithToolQuota(t *testing.T) {
+	tracker, err := newQuotaTracker(map[string]toolQuota{"search": {Limit: 1, Window: time.Hour}}, filepath.Join(t.TempDir(), "quotas.json"))
+	require.NoError(t, err)
+
+	calls := 0
+	handler := tracker.withToolQuota("search", func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return &mcp.CallToolResult{}, nil
+	})
+
+	_, err = handler(t.Context(), &mcp.CallToolRequest{})
+	require.NoError(t, err)
+	_, err = handler(t.Context(), &mcp.CallToolRequest{})
+	require.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	var noQuotas *quotaTracker
+	assert.NotNil(t, noQuotas.withToolQuota("search", handler))
+}
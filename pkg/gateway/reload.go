@@ -3,6 +3,7 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,6 +19,7 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 	if len(serverNames) == 0 {
 		serverNames = configuration.ServerNames()
 	}
+	serverNames = supportedServers(configuration, serverNames)
 	if len(serverNames) == 0 {
 		log.Log("- No server is enabled")
 	} else {
@@ -33,36 +35,46 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 	}
 	log.Log(">", len(capabilities.Tools), "tools listed in", time.Since(startList))
 
+	// Servers that failed don't prevent the others from being exposed
+	g.degraded.retain(serverNames)
+	if failures := g.degraded.list(); len(failures) > 0 {
+		var failedServers []string
+		for _, failure := range failures {
+			failedServers = append(failedServers, failure.Server)
+		}
+		log.Warn(fmt.Sprintf("%d server(s) are degraded, their tools are not available: %s", len(failures), strings.Join(failedServers, ", ")))
+	}
+
 	// Update capabilities
-	// Clear existing capabilities per server and register new ones
+	// Register the new capabilities over the old ones, then remove the ones that are gone,
+	// so that clients never see a capability that's still provided vanish during a reload.
 
 	// Lock for reading/writing capability tracking
 	g.capabilitiesMu.Lock()
 	defer g.capabilitiesMu.Unlock()
 
-	// Clear all existing capabilities from tracked servers
-	for _, oldCaps := range g.serverCapabilities {
-		if len(oldCaps.ToolNames) > 0 {
-			g.mcpServer.RemoveTools(oldCaps.ToolNames...)
-		}
-		if len(oldCaps.PromptNames) > 0 {
-			g.mcpServer.RemovePrompts(oldCaps.PromptNames...)
-		}
-		if len(oldCaps.ResourceURIs) > 0 {
-			g.mcpServer.RemoveResources(oldCaps.ResourceURIs...)
-		}
-		if len(oldCaps.ResourceTemplateURIs) > 0 {
-			g.mcpServer.RemoveResourceTemplates(oldCaps.ResourceTemplateURIs...)
-		}
+	// Remember what was registered, to remove what's not provided anymore
+	oldCaps := mergeServerCapabilities(g.serverCapabilities)
+	for toolName := range g.toolRegistrations {
+		oldCaps.ToolNames = append(oldCaps.ToolNames, toolName)
 	}
 
 	// Clear the tracking maps - we'll rebuild them
 	g.serverCapabilities = make(map[string]*ServerCapabilities)
 	g.toolRegistrations = make(map[string]ToolRegistration)
 
+	// Two servers exposing the same tool name shadow each other: only one is exposed
+	for _, collision := range findToolCollisions(capabilities.Tools) {
+		log.Warn(fmt.Sprintf("tool '%s' is provided by several servers (%s), only one of them is exposed. Use --tool-name-prefix to expose all of them.",
+			collision.ToolName, strings.Join(collision.ServerNames, ", ")))
+	}
+
 	// Add new capabilities and track them per server
 	for _, tool := range capabilities.Tools {
-		g.mcpServer.AddTool(tool.Tool, tool.Handler)
+		tool.Handler = g.wrapToolHandler(tool)
+		if g.isToolListed(tool) {
+			g.mcpServer.AddTool(tool.Tool, tool.Handler)
+		}
 
 		// Track by server
 		if g.serverCapabilities[tool.ServerName] == nil {
@@ -82,41 +94,81 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		log.Log("- Adding internal tools (dynamic-tools feature enabled)")
 
 		// Add mcp-find tool
-		mcpFindTool := g.createMcpFindTool(configuration)
-		g.mcpServer.AddTool(mcpFindTool.Tool, mcpFindTool.Handler)
-		g.toolRegistrations[mcpFindTool.Tool.Name] = *mcpFindTool
+		g.addInternalTool(g.createMcpFindTool())
+
+		// Add explain-match tool
+		g.addInternalTool(g.createExplainMatchTool())
 
 		// Add mcp-add tool
-		mcpAddTool := g.createMcpAddTool(clientConfig)
-		g.mcpServer.AddTool(mcpAddTool.Tool, mcpAddTool.Handler)
-		g.toolRegistrations[mcpAddTool.Tool.Name] = *mcpAddTool
+		g.addInternalTool(g.createMcpAddTool(clientConfig))
 
 		// Add mcp-remove tool
-		mcpRemoveTool := g.createMcpRemoveTool()
-		g.mcpServer.AddTool(mcpRemoveTool.Tool, mcpRemoveTool.Handler)
-		g.toolRegistrations[mcpRemoveTool.Tool.Name] = *mcpRemoveTool
+		g.addInternalTool(g.createMcpRemoveTool())
 
 		// Add codemode
-		codeModeTool := g.createCodeModeTool(clientConfig)
-		g.mcpServer.AddTool(codeModeTool.Tool, codeModeTool.Handler)
-		g.toolRegistrations[codeModeTool.Tool.Name] = *codeModeTool
+		g.addInternalTool(g.createCodeModeTool(clientConfig))
 
 		// Add mcp-exec tool
-		mcpExecTool := g.createMcpExecTool()
-		g.mcpServer.AddTool(mcpExecTool.Tool, mcpExecTool.Handler)
-		g.toolRegistrations[mcpExecTool.Tool.Name] = *mcpExecTool
+		g.addInternalTool(g.createMcpExecTool())
+
+		// Add list-tools tool
+		g.addInternalTool(g.createListToolsTool())
+
+		// Add describe-tool tool
+		g.addInternalTool(g.createDescribeToolTool())
+
+		// Add stats tool
+		g.addInternalTool(g.createStatsTool())
+
+		// Add degraded-servers tool
+		g.addInternalTool(g.createDegradedServersTool())
+
+		// Add catalog-stats tool
+		g.addInternalTool(g.createCatalogStatsTool())
+
+		// Add probe-server tool
+		g.addInternalTool(g.createProbeServerTool())
+
+		// Add clear-caches tool
+		g.addInternalTool(g.createClearCachesTool())
+
+		// Add dump-config tool
+		g.addInternalTool(g.createDumpConfigTool())
 
 		// Add mcp-config-set tool (also handles secrets with secret=true)
-		mcpConfigSetTool := g.createMcpConfigSetTool(clientConfig)
-		g.mcpServer.AddTool(mcpConfigSetTool.Tool, mcpConfigSetTool.Handler)
-		g.toolRegistrations[mcpConfigSetTool.Tool.Name] = *mcpConfigSetTool
+		g.addInternalTool(g.createMcpConfigSetTool(clientConfig))
+
+		// Add record-tool-feedback tool when the feedback ranks the mcp-find results
+		if g.findFeedback != nil {
+			g.addInternalTool(g.createRecordToolFeedbackTool(configuration))
+		}
 
 		log.Log("  > mcp-find: tool for finding MCP servers in the catalog")
+		log.Log("  > explain-match: explain how mcp-find scores a server for a query")
 		log.Log("  > mcp-add: tool for adding MCP servers to the registry")
 		log.Log("  > mcp-remove: tool for removing MCP servers from the registry")
-		log.Log("  > mcp-config-set: tool for setting config values (use secret=true for secrets)")
+		if g.ReadOnlyConfig {
+			log.Log("  > mcp-config-set: disabled, the configuration is read-only")
+		} else {
+			log.Log("  > mcp-config-set: tool for setting config values (use secret=true for secrets)")
+		}
 		log.Log("  > code-mode: write code that calls other MCPs directly")
 		log.Log("  > mcp-exec: execute tools that exist in the current session")
+		log.Log("  > list-tools: list all the tools exposed by the gateway")
+		log.Log("  > describe-tool: describe how to call a tool")
+		log.Log("  > stats: report tool call and search counters")
+		log.Log("  > degraded-servers: list the servers that failed to start")
+		log.Log("  > catalog-stats: report aggregate statistics about the catalog")
+		if g.ReadOnlyConfig {
+			log.Log("  > probe-server: disabled, the configuration is read-only")
+		} else {
+			log.Log("  > probe-server: start a server and list its tools live")
+		}
+		log.Log("  > clear-caches: empty the gateway's caches")
+		log.Log("  > dump-config: show the effective configuration of the servers")
+		if g.findFeedback != nil {
+			log.Log("  > record-tool-feedback: report which tool was useful for an mcp-find query")
+		}
 
 		// Add mcp-registry-import tool
 		// mcpRegistryImportTool := g.createMcpRegistryImportTool(configuration, clientConfig)
@@ -135,6 +187,18 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		log.Log("  > mcp-discover: prompt for learning about dynamic server management")
 	}
 
+	// Add quota-status tool when tools have quotas
+	if g.quotas != nil {
+		g.addInternalTool(g.createQuotaStatusTool())
+		log.Log("  > quota-status: report the remaining quota of each tool")
+	}
+
+	// Add the tools registered by the embedder
+	for _, toolReg := range g.registeredInternalTools() {
+		g.addInternalTool(&toolReg)
+		log.Log("  > " + toolReg.Tool.Name + ": custom internal tool")
+	}
+
 	for _, prompt := range capabilities.Prompts {
 		g.mcpServer.AddPrompt(prompt.Prompt, prompt.Handler)
 
@@ -182,11 +246,62 @@ func (g *Gateway) reloadConfiguration(ctx context.Context, configuration Configu
 		)
 	}
 
+	// Only now, remove the capabilities that are not provided anymore, or not listed anymore
+	newCaps := mergeServerCapabilities(g.serverCapabilities)
+	newCaps.ToolNames = nil
+	for toolName, tool := range g.toolRegistrations {
+		if g.isToolListed(tool) {
+			newCaps.ToolNames = append(newCaps.ToolNames, toolName)
+		}
+	}
+	if _, removed := diffStringSlices(oldCaps.ToolNames, newCaps.ToolNames); len(removed) > 0 {
+		g.mcpServer.RemoveTools(removed...)
+	}
+	if _, removed := diffStringSlices(oldCaps.PromptNames, newCaps.PromptNames); len(removed) > 0 {
+		g.mcpServer.RemovePrompts(removed...)
+	}
+	if _, removed := diffStringSlices(oldCaps.ResourceURIs, newCaps.ResourceURIs); len(removed) > 0 {
+		g.mcpServer.RemoveResources(removed...)
+	}
+	if _, removed := diffStringSlices(oldCaps.ResourceTemplateURIs, newCaps.ResourceTemplateURIs); len(removed) > 0 {
+		g.mcpServer.RemoveResourceTemplates(removed...)
+	}
+
 	g.health.SetHealthy()
 
 	return nil
 }
 
+// wrapToolHandler applies the quota, the result cache and the usage stats to the handler of a tool.
+// Every path that exposes a tool to the clients goes through it.
+func (g *Gateway) wrapToolHandler(tool ToolRegistration) mcp.ToolHandler {
+	handler := g.quotas.withToolQuota(tool.Tool.Name, tool.Handler)
+	if tool.Cacheable {
+		// Cache hits don't consume the quota
+		handler = g.toolCache.withToolCache(tool.Tool.Name, handler)
+	}
+	return g.stats.withToolStats(tool.Tool.Name, handler)
+}
+
+// isToolListed tells whether a tool is listed to the client. With --discoverable-tools, only the
+// gateway's own tools and the always-on tools are. The others are still reachable with
+// list-tools, describe-tool and mcp-exec.
+func (g *Gateway) isToolListed(tool ToolRegistration) bool {
+	return !g.DiscoverableTools || tool.ServerName == "" || tool.AlwaysOn
+}
+
+// mergeServerCapabilities returns the capabilities registered by all the servers
+func mergeServerCapabilities(serverCapabilities map[string]*ServerCapabilities) *ServerCapabilities {
+	merged := &ServerCapabilities{}
+	for _, caps := range serverCapabilities {
+		merged.ToolNames = append(merged.ToolNames, caps.ToolNames...)
+		merged.PromptNames = append(merged.PromptNames, caps.PromptNames...)
+		merged.ResourceURIs = append(merged.ResourceURIs, caps.ResourceURIs...)
+		merged.ResourceTemplateURIs = append(merged.ResourceTemplateURIs, caps.ResourceTemplateURIs...)
+	}
+	return merged
+}
+
 // stringSliceToSet converts a slice to a map for efficient lookup
 func stringSliceToSet(slice []string) map[string]bool {
 	set := make(map[string]bool, len(slice))
@@ -250,7 +365,8 @@ func (g *Gateway) allCapabilities(serverName string) *ServerCapabilities {
 
 func (g *Gateway) reloadServerCapabilities(ctx context.Context, serverName string, clientConfig *clientConfig) (*ServerCapabilities, error) {
 	// Find the server configuration in current config
-	serverConfig, _, found := g.configuration.Find(serverName)
+	configuration := g.currentConfiguration()
+	serverConfig, _, found := configuration.Find(serverName)
 	if !found || serverConfig == nil {
 		return nil, fmt.Errorf("server %s not found in configuration", serverName)
 	}
@@ -271,7 +387,10 @@ func (g *Gateway) reloadServerCapabilities(ctx context.Context, serverName strin
 		oldCaps = &ServerCapabilities{}
 	}
 
-	// Store the full capabilities
+	// Store the full capabilities, with the same handlers as a full reload would register
+	for i := range newServerCaps.Tools {
+		newServerCaps.Tools[i].Handler = g.wrapToolHandler(newServerCaps.Tools[i])
+	}
 	g.serverAvailableCapabilities[serverName] = newServerCaps
 
 	// Update tool registrations for this server
@@ -348,7 +467,7 @@ func (g *Gateway) updateServerCapabilities(serverName string, oldCaps, newCaps *
 		if toolFilterSet != nil && !toolFilterSet[tool] {
 			continue
 		}
-		if registration, err := newServerCaps.getToolByName(tool); err == nil {
+		if registration, err := newServerCaps.getToolByName(tool); err == nil && g.isToolListed(registration) {
 			g.mcpServer.AddTool(registration.Tool, registration.Handler)
 			toolsAdded++
 		}
@@ -392,7 +511,8 @@ func (g *Gateway) updateServerCapabilities(serverName string, oldCaps, newCaps *
 
 func (g *Gateway) removeServerConfiguration(_ context.Context, serverName string) error {
 	// Find the server configuration in current config
-	serverConfig, _, found := g.configuration.Find(serverName)
+	configuration := g.currentConfiguration()
+	serverConfig, _, found := configuration.Find(serverName)
 	if !found || serverConfig == nil {
 		return fmt.Errorf("server %s not found in configuration", serverName)
 	}
@@ -433,3 +553,42 @@ func (g *Gateway) removeServerConfiguration(_ context.Context, serverName string
 
 	return nil
 }
+
+// RegisterInternalTool adds a tool alongside the gateway's own internal tools.
+// It doesn't belong to any server and is exposed to the clients on the next reload.
+// Registering a tool with the same name replaces the previous one.
+func (g *Gateway) RegisterInternalTool(toolReg *ToolRegistration) {
+	g.customToolsMu.Lock()
+	defer g.customToolsMu.Unlock()
+
+	customTool := *toolReg
+	customTool.ServerName = ""
+
+	for i, existing := range g.customTools {
+		if existing.Tool.Name == customTool.Tool.Name {
+			g.customTools[i] = customTool
+			return
+		}
+	}
+	g.customTools = append(g.customTools, customTool)
+}
+
+func (g *Gateway) registeredInternalTools() []ToolRegistration {
+	g.customToolsMu.Lock()
+	defer g.customToolsMu.Unlock()
+
+	return slices.Clone(g.customTools)
+}
+
+// addInternalTool exposes one of the gateway's own tools, bounded by the internal tool timeout.
+func (g *Gateway) addInternalTool(toolReg *ToolRegistration) {
+	toolReg.Handler = withToolTimeout(toolReg.Tool.Name, g.InternalToolTimeout, toolReg.Handler)
+	toolReg.Handler = g.wrapToolHandler(*toolReg)
+
+	if existing, exists := g.toolRegistrations[toolReg.Tool.Name]; exists {
+		log.Warn(fmt.Sprintf("tool '%s' of server '%s' is shadowed by the gateway's own tool", existing.Tool.Name, existing.ServerName))
+	}
+
+	g.mcpServer.AddTool(toolReg.Tool, toolReg.Handler)
+	g.toolRegistrations[toolReg.Tool.Name] = *toolReg
+}
@@ -0,0 +1,224 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func customTool(name, text string) *ToolRegistration {
+	return &ToolRegistration{
+		ServerName: "ignored",
+		Tool:       &mcp.Tool{Name: name, InputSchema: &jsonschema.Schema{Type: "object"}},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil
+		},
+	}
+}
+
+func TestRegisterInternalTool(t *testing.T) {
+	g := &Gateway{
+		toolRegistrations: make(map[string]ToolRegistration),
+		mcpServer:         mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil),
+	}
+
+	g.RegisterInternalTool(customTool("hello", "v1"))
+	g.RegisterInternalTool(customTool("hello", "v2"))
+	g.RegisterInternalTool(customTool("bye", "bye"))
+
+	// Not exposed until the next reload
+	assert.Empty(t, g.toolRegistrations)
+
+	err := g.reloadConfiguration(t.Context(), Configuration{}, nil, nil)
+	require.NoError(t, err)
+
+	require.Contains(t, g.toolRegistrations, "hello")
+	require.Contains(t, g.toolRegistrations, "bye")
+	assert.Empty(t, g.toolRegistrations["hello"].ServerName)
+
+	result, err := g.toolRegistrations["hello"].Handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "hello"}})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestReloadOnlyRemovesStaleTools(t *testing.T) {
+	g := &Gateway{
+		toolRegistrations: make(map[string]ToolRegistration),
+		mcpServer:         mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil),
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err := g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	listToolNames := func() []string {
+		result, err := session.ListTools(t.Context(), nil)
+		require.NoError(t, err)
+
+		var names []string
+		for _, tool := range result.Tools {
+			names = append(names, tool.Name)
+		}
+		return names
+	}
+
+	g.RegisterInternalTool(customTool("hello", "v1"))
+	g.RegisterInternalTool(customTool("bye", "bye"))
+	require.NoError(t, g.reloadConfiguration(t.Context(), Configuration{}, nil, nil))
+	assert.ElementsMatch(t, []string{"hello", "bye"}, listToolNames())
+
+	// Drop one tool and update the other
+	g.customTools = []ToolRegistration{*customTool("hello", "v2")}
+	require.NoError(t, g.reloadConfiguration(t.Context(), Configuration{}, nil, nil))
+	assert.Equal(t, []string{"hello"}, listToolNames())
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "hello"})
+	require.NoError(t, err)
+	assert.Equal(t, "v2", result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestIsToolListed(t *testing.T) {
+	internal := ToolRegistration{Tool: &mcp.Tool{Name: "mcp-find"}}
+	core := ToolRegistration{ServerName: "github", Tool: &mcp.Tool{Name: "get_me"}, AlwaysOn: true}
+	discoverable := ToolRegistration{ServerName: "github", Tool: &mcp.Tool{Name: "list_issues"}}
+
+	g := &Gateway{}
+	assert.True(t, g.isToolListed(internal))
+	assert.True(t, g.isToolListed(core))
+	assert.True(t, g.isToolListed(discoverable))
+
+	g.DiscoverableTools = true
+	assert.True(t, g.isToolListed(internal))
+	assert.True(t, g.isToolListed(core))
+	assert.False(t, g.isToolListed(discoverable))
+}
+
+// remoteTestServer serves an MCP server with the given tools over streamable http, and returns
+// its catalog entry and the server.
+func remoteTestServer(t *testing.T, toolNames ...string) (catalog.Server, *mcp.Server) {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "remote", Version: "1.0.0"}, nil)
+	for _, toolName := range toolNames {
+		server.AddTool(&mcp.Tool{Name: toolName, InputSchema: &jsonschema.Schema{Type: "object"}}, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: toolName}}}, nil
+		})
+	}
+
+	httpServer := httptest.NewServer(mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server { return server }, nil))
+	t.Cleanup(httpServer.Close)
+
+	return catalog.Server{Type: "remote", Remote: catalog.Remote{URL: httpServer.URL, Transport: "http"}}, server
+}
+
+func TestAddedServerToolsAreQuotaLimited(t *testing.T) {
+	quotas, err := newQuotaTracker(map[string]toolQuota{"search": {Limit: 1, Window: time.Hour}}, filepath.Join(t.TempDir(), "quotas.json"))
+	require.NoError(t, err)
+	remote, _ := remoteTestServer(t, "search")
+
+	g := &Gateway{
+		configuration: Configuration{
+			serverNames: []string{"remote"},
+			servers:     map[string]catalog.Server{"remote": remote},
+		},
+		quotas:                      quotas,
+		toolRegistrations:           make(map[string]ToolRegistration),
+		serverCapabilities:          make(map[string]*ServerCapabilities),
+		serverAvailableCapabilities: make(map[string]*Capabilities),
+		mcpServer:                   mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil),
+	}
+	g.clientPool = newClientPool(Options{}, nil, g)
+
+	// What mcp-add does
+	oldCaps, err := g.reloadServerCapabilities(t.Context(), "remote", nil)
+	require.NoError(t, err)
+	g.capabilitiesMu.Lock()
+	err = g.updateServerCapabilities("remote", oldCaps, g.allCapabilities("remote"), nil)
+	g.capabilitiesMu.Unlock()
+	require.NoError(t, err)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	_, err = g.mcpServer.Connect(t.Context(), serverTransport, nil)
+	require.NoError(t, err)
+	session, err := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil).Connect(t.Context(), clientTransport, nil)
+	require.NoError(t, err)
+	defer session.Close()
+
+	// The quota is used up, the call doesn't reach the server
+	require.NoError(t, quotas.consume("search"))
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "search"})
+	if err == nil {
+		require.True(t, result.IsError)
+		assert.Contains(t, result.Content[0].(*mcp.TextContent).Text, `quota exceeded for tool "search"`)
+	} else {
+		assert.ErrorContains(t, err, `quota exceeded for tool "search"`)
+	}
+
+	// The registration used by mcp-exec is limited too
+	_, err = g.toolRegistrations["search"].Handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Name: "search"}})
+	require.ErrorContains(t, err, `quota exceeded for tool "search"`)
+}
+
+func TestConfigurationUpdateIsSearched(t *testing.T) {
+	g := &Gateway{
+		Options: Options{FindCacheTTL: time.Minute},
+		configuration: Configuration{
+			servers: map[string]catalog.Server{
+				"github": {Description: "Manage GitHub issues"},
+			},
+		},
+		toolRegistrations:           make(map[string]ToolRegistration),
+		serverCapabilities:          make(map[string]*ServerCapabilities),
+		serverAvailableCapabilities: make(map[string]*Capabilities),
+		mcpServer:                   mcp.NewServer(&mcp.Implementation{Name: "test-gateway", Version: "1.0.0"}, nil),
+	}
+	g.clientPool = newClientPool(Options{}, nil, g)
+	mcpFind := g.createMcpFindTool()
+
+	find := func() []string {
+		result := callInternalTool(t, mcpFind, map[string]any{"query": "issues", "include_disabled": true})
+		require.False(t, result.IsError)
+
+		var response struct {
+			Servers []struct {
+				Name string `json:"name"`
+			} `json:"servers"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(*mcp.TextContent).Text), &response))
+
+		var names []string
+		for _, server := range response.Servers {
+			names = append(names, server.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	assert.Equal(t, []string{"github"}, find())
+
+	// The catalog changed on disk
+	g.applyConfigurationUpdate(t.Context(), Configuration{
+		servers: map[string]catalog.Server{
+			"github": {Description: "Manage GitHub issues"},
+			"gitlab": {Description: "Manage GitLab issues"},
+		},
+	})
+
+	assert.Equal(t, []string{"github", "gitlab"}, find())
+}
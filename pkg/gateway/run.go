@@ -8,6 +8,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -49,14 +50,16 @@ type ServerCapabilities struct {
 
 type Gateway struct {
 	Options
-	docker         docker.Client
-	configurator   Configurator
-	configuration  Configuration
-	clientPool     *clientPool
-	mcpServer      *mcp.Server
-	health         health.State
-	oauthProviders map[string]*oauth.Provider
-	providersMu    sync.RWMutex
+	docker        docker.Client
+	configurator  Configurator
+	configuration Configuration
+	// Guards the configuration, whose enabled servers are changed by mcp-add and mcp-remove
+	configurationMu sync.RWMutex
+	clientPool      *clientPool
+	mcpServer       *mcp.Server
+	health          health.State
+	oauthProviders  map[string]*oauth.Provider
+	providersMu     sync.RWMutex
 	// subsChannel  chan SubsMessage
 
 	sessionCacheMu sync.RWMutex
@@ -70,10 +73,49 @@ type Gateway struct {
 	// Track all tool registrations for mcp-exec
 	toolRegistrations map[string]ToolRegistration
 
+	// Counters reported by the stats tool
+	stats gatewayStats
+
+	// Enabled servers that failed to start or to list their tools
+	degraded degradedServers
+
+	// Cache of the current mcp-find tool, replaced on every reload
+	findCache atomic.Pointer[findCache]
+
+	// Resources released by Close
+	closeMu sync.Mutex
+	closers []func() error
+
+	// Per-tool quotas, nil when there are none
+	quotas *quotaTracker
+
+	// Log of the mcp-find searches, nil unless --find-log is set
+	findLog *findLog
+
+	// Servers reported useful for mcp-find queries, nil unless --find-feedback-weight is set
+	findFeedback *findFeedback
+
+	// Results of the cacheable tools, nil unless --tool-cache-ttl is set
+	toolCache *toolResultCache
+
+	// Tools added with RegisterInternalTool, exposed on every reload
+	customToolsMu sync.Mutex
+	customTools   []ToolRegistration
+
 	// authToken stores the authentication token for SSE/streaming modes
 	authToken string
 	// authTokenWasGenerated indicates whether the token was auto-generated or from environment
 	authTokenWasGenerated bool
+
+	// ReRanker, when set, is applied to the scored mcp-find candidates before they are
+	// truncated to the requested limit. It lets embedders inject their own ranking
+	// (boost internal servers, demote deprecated ones, ...). Defaults to identity.
+	ReRanker func(ctx context.Context, query string, candidates []ServerMatch) []ServerMatch
+
+	// QueryPreprocessor, when set, rewrites the mcp-find and explain-match queries before
+	// they are scored. It lets embedders normalize them (strip filler words, append domain
+	// context, ...). Defaults to identity.
+	QueryPreprocessor func(ctx context.Context, query string) string
 }
 
 func NewGateway(config Config, docker docker.Client) *Gateway {
@@ -107,6 +149,7 @@ func NewGateway(config Config, docker docker.Client) *Gateway {
 			OciRef:             config.OciRef,
 			MCPRegistryServers: config.MCPRegistryServers,
 			Watch:              config.Watch,
+			PollInterval:       config.PollInterval,
 			McpOAuthDcrEnabled: config.McpOAuthDcrEnabled,
 			sessionName:        config.SessionName,
 			docker:             docker,
@@ -129,9 +172,91 @@ func NewGateway(config Config, docker docker.Client) *Gateway {
 }
 
 func (g *Gateway) Run(ctx context.Context) error {
+	// Validate the memory limit before anything gets started
+	memory, err := normalizeMemory(g.Memory)
+	if err != nil {
+		return err
+	}
+	g.Memory = memory
+	g.clientPool.Memory = memory
+
+	if err := validatePopularityRanking(g.FindRankField, g.FindRankWeight); err != nil {
+		return err
+	}
+	rankWeight := 0.0
+	if g.FindRankField != "" {
+		rankWeight = g.FindRankWeight
+	}
+	if err := validateFindFeedbackWeight(g.FindFeedbackWeight, rankWeight); err != nil {
+		return err
+	}
+	if err := validateJSONFormat(g.JSONFormat); err != nil {
+		return err
+	}
+
+	// Close cancels everything that runs until the context is done
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	g.onClose(func() error {
+		cancel()
+		return nil
+	})
+
 	// Initialize telemetry
 	telemetry.Init()
 
+	// Set up the log level. --verbose implies debug.
+	switch {
+	case g.Verbose:
+		log.SetLevel(log.LevelDebug)
+	case g.LogLevel != "":
+		level, err := log.ParseLevel(g.LogLevel)
+		if err != nil {
+			return err
+		}
+		log.SetLevel(level)
+	}
+
+	if len(g.ToolQuotas) > 0 {
+		quotas, err := loadToolQuotas(g.ToolQuotas, g.InternalToolsDir)
+		if err != nil {
+			return err
+		}
+		g.quotas = quotas
+	}
+
+	if g.RunPolicyPath != "" {
+		if g.AllowPrivilegedRunArgs {
+			return fmt.Errorf("--run-policy and --allow-privileged-run-args can't be used together")
+		}
+		policy, err := loadRunPolicy(g.RunPolicyPath)
+		if err != nil {
+			return err
+		}
+		g.clientPool.runPolicy = policy
+	}
+
+	if g.FindFeedbackWeight > 0 {
+		findFeedback, err := loadFindFeedback(g.InternalToolsDir)
+		if err != nil {
+			return err
+		}
+		g.findFeedback = findFeedback
+	}
+
+	if g.ToolCacheTTL > 0 {
+		g.toolCache = newToolResultCache(g.ToolCacheTTL, &g.stats)
+	}
+
+	if g.FindLogPath != "" {
+		findLog, err := openFindLog(g.FindLogPath)
+		if err != nil {
+			return err
+		}
+		g.findLog = findLog
+		g.onClose(findLog.Close)
+	}
+
 	// Set up log file redirection if specified
 	if g.LogFilePath != "" {
 		logFile, err := os.OpenFile(g.LogFilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
@@ -145,6 +270,11 @@ func (g *Gateway) Run(ctx context.Context) error {
 		log.SetLogWriter(multiWriter)
 	}
 
+	if g.DiscoverableTools && !g.DynamicTools {
+		log.Warn("--discoverable-tools is ignored without the dynamic tools, all the tools are listed")
+		g.DiscoverableTools = false
+	}
+
 	// Record gateway start
 	transportMode := "stdio"
 	if g.Port != 0 {
@@ -186,16 +316,20 @@ func (g *Gateway) Run(ctx context.Context) error {
 
 	// Read the configuration.
 	configuration, configurationUpdates, stopConfigWatcher, err := g.configurator.Read(ctx)
-	g.configuration = configuration
+	configuration.allowedEnv = g.AllowedEnvReferences
+	g.setConfiguration(configuration)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = stopConfigWatcher() }()
+	g.onClose(stopConfigWatcher)
 
 	// Set the session name in the configuration for persistence if specified via --session flag
 	if fbc, ok := g.configurator.(*FileBasedConfiguration); ok {
 		if fbc.sessionName != "" {
-			g.configuration.SessionName = fbc.sessionName
+			g.updateConfiguration(func(configuration *Configuration) {
+				configuration.SessionName = fbc.sessionName
+			})
 		}
 	}
 
@@ -309,18 +443,7 @@ func (g *Gateway) Run(ctx context.Context) error {
 					log.Log("> Stop watching for updates")
 					return
 				case configuration := <-configurationUpdates:
-					log.Log("> Configuration updated, reloading...")
-
-					if err := g.pullAndVerify(ctx, configuration); err != nil {
-						log.Logf("> Unable to pull and verify images: %s", err)
-						continue
-					}
-
-					if err := g.reloadConfiguration(ctx, configuration, nil, nil); err != nil {
-						log.Logf("> Unable to list capabilities: %s", err)
-						g.configuration = configuration
-						continue
-					}
+					g.applyConfigurationUpdate(ctx, configuration)
 				}
 			}
 		}()
@@ -387,6 +510,26 @@ func (g *Gateway) Run(ctx context.Context) error {
 	}
 }
 
+// applyConfigurationUpdate switches to a configuration read again after its files or URLs changed.
+func (g *Gateway) applyConfigurationUpdate(ctx context.Context, configuration Configuration) {
+	log.Log("> Configuration updated, reloading...")
+	configuration.allowedEnv = g.AllowedEnvReferences
+	configuration.SessionName = g.currentConfiguration().SessionName
+
+	if err := g.pullAndVerify(ctx, configuration); err != nil {
+		log.Logf("> Unable to pull and verify images: %s", err)
+		return
+	}
+
+	// The tools, e.g. mcp-find, and the capabilities read the new configuration
+	g.setConfiguration(configuration)
+	g.clearFindCache()
+
+	if err := g.reloadConfiguration(ctx, configuration, nil, nil); err != nil {
+		log.Logf("> Unable to list capabilities: %s", err)
+	}
+}
+
 // RefreshCapabilities implements the CapabilityRefresher interface
 // This method updates the server's capabilities by reloading the configuration
 func (g *Gateway) RefreshCapabilities(ctx context.Context, server *mcp.Server, serverSession *mcp.ServerSession, serverName string) error {
@@ -0,0 +1,258 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+// privilegedRunArgs are the docker run flags that weaken the isolation of a container.
+// --cap-add is not one of them: the capabilities it adds are checked by the run policy.
+var privilegedRunArgs = []string{
+	"--privileged",
+	"--security-opt",
+	"--device",
+	"--device-cgroup-rule",
+	"--cgroup-parent",
+	"--cgroupns",
+	"--pid",
+	"--ipc",
+	"--uts",
+	"--userns",
+	"--user",
+	"--group-add",
+	"--sysctl",
+	"--runtime",
+	"--volumes-from",
+	"--volume",
+	"--mount",
+}
+
+// safeRunArgs are the docker run flags that the registry's server entries can use by default.
+// None of them gives the container more access to the host.
+var safeRunArgs = []string{
+	"--add-host",
+	"--cap-add",
+	"--cpu-shares",
+	"--cpus",
+	"--dns",
+	"--dns-option",
+	"--dns-search",
+	"--env",
+	"--hostname",
+	"--label",
+	"--memory",
+	"--memory-reservation",
+	"--memory-swap",
+	"--network",
+	"--pids-limit",
+	"--read-only",
+	"--shm-size",
+	"--stop-signal",
+	"--stop-timeout",
+	"--tmpfs",
+	"--workdir",
+}
+
+// shortRunFlags maps the short docker run flags to their long form.
+var shortRunFlags = map[byte]string{
+	'a': "--attach",
+	'c': "--cpu-shares",
+	'd': "--detach",
+	'e': "--env",
+	'h': "--hostname",
+	'i': "--interactive",
+	'l': "--label",
+	'm': "--memory",
+	'p': "--publish",
+	'P': "--publish-all",
+	'q': "--quiet",
+	't': "--tty",
+	'u': "--user",
+	'v': "--volume",
+	'w': "--workdir",
+}
+
+// runFlagAliases are the long docker run flags that have another name.
+var runFlagAliases = map[string]string{
+	"--net":       "--network",
+	"--net-alias": "--network-alias",
+	"--dns-opt":   "--dns-option",
+}
+
+// booleanRunFlags are the docker run flags that don't take a value.
+var booleanRunFlags = []string{
+	"--detach",
+	"--disable-content-trust",
+	"--help",
+	"--init",
+	"--interactive",
+	"--no-healthcheck",
+	"--oom-kill-disable",
+	"--privileged",
+	"--publish-all",
+	"--quiet",
+	"--read-only",
+	"--rm",
+	"--sig-proxy",
+	"--tty",
+	"--use-api-socket",
+}
+
+// runArg is a docker run flag, in its long form, and its value.
+type runArg struct {
+	flag  string
+	value string
+	// text is the arg as it was written, for the error messages
+	text string
+}
+
+// normalizeRunFlag returns the long form of a docker run flag, e.g. --volume for -v.
+func normalizeRunFlag(flag string) string {
+	if len(flag) == 2 && flag[0] == '-' {
+		if long, ok := shortRunFlags[flag[1]]; ok {
+			return long
+		}
+	}
+	if canonical, ok := runFlagAliases[flag]; ok {
+		return canonical
+	}
+	return flag
+}
+
+// parseRunArgs splits docker run args into flags and values, the way the docker cli does:
+// --flag=value, --flag value, -fvalue, -f value and grouped short flags (-it) are all understood.
+// Every arg must be a flag or the value of a flag: the image comes after them.
+func parseRunArgs(args []string) ([]runArg, error) {
+	var parsed []runArg
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case strings.HasPrefix(arg, "--") && len(arg) > 2:
+			flag, value, hasValue := strings.Cut(arg, "=")
+			flag = normalizeRunFlag(flag)
+			if !hasValue && !slices.Contains(booleanRunFlags, flag) {
+				if i+1 >= len(args) {
+					return nil, fmt.Errorf("run arg %q is missing a value", arg)
+				}
+				i++
+				value = args[i]
+			}
+			parsed = append(parsed, runArg{flag: flag, value: value, text: arg})
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1 && arg[1] != '-':
+			// Short flags can be grouped, and the last one can have its value attached
+			for j := 1; j < len(arg); j++ {
+				flag := normalizeRunFlag("-" + arg[j:j+1])
+				if slices.Contains(booleanRunFlags, flag) {
+					parsed = append(parsed, runArg{flag: flag, text: arg})
+					continue
+				}
+
+				value := strings.TrimPrefix(arg[j+1:], "=")
+				if j+1 == len(arg) {
+					if i+1 >= len(args) {
+						return nil, fmt.Errorf("run arg %q is missing a value", arg)
+					}
+					i++
+					value = args[i]
+				}
+				parsed = append(parsed, runArg{flag: flag, value: value, text: arg})
+				break
+			}
+
+		default:
+			return nil, fmt.Errorf("run arg %q is not a flag", arg)
+		}
+	}
+
+	return parsed, nil
+}
+
+// validateExtraRunArgs rejects the extra docker run args that the run policy doesn't allow.
+func validateExtraRunArgs(args []string, policy *runPolicy) error {
+	if err := policy.checkRunArgs(args); err != nil {
+		return fmt.Errorf("extra run args: %w", err)
+	}
+
+	return nil
+}
+
+// validateVolumeMounts rejects the mounts of host paths that are not under one of the allowed roots.
+func validateVolumeMounts(mounts []catalog.VolumeMount, allowedRoots []string) error {
+	for _, mount := range mounts {
+		if mount.HostPath == "" || mount.ContainerPath == "" {
+			return fmt.Errorf("volume %s:%s must have a host path and a container path", mount.HostPath, mount.ContainerPath)
+		}
+		if !filepath.IsAbs(mount.HostPath) || !path.IsAbs(mount.ContainerPath) {
+			return fmt.Errorf("volume %s:%s must use absolute paths", mount.HostPath, mount.ContainerPath)
+		}
+		// They would change the options of the -v flag, e.g. /data:/workspace:rw
+		hostPath := strings.TrimPrefix(mount.HostPath, filepath.VolumeName(mount.HostPath))
+		if strings.ContainsAny(hostPath, ":,") || strings.ContainsAny(mount.ContainerPath, ":,") {
+			return fmt.Errorf("volume %s:%s can't have ':' or ',' in its paths", mount.HostPath, mount.ContainerPath)
+		}
+
+		resolved := resolvePath(mount.HostPath)
+		allowed := false
+		for _, root := range allowedRoots {
+			if isUnder(resolved, resolvePath(root)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("mounting %s is not allowed (use --allowed-mount-root to allow it)", mount.HostPath)
+		}
+	}
+
+	return nil
+}
+
+// resolvePath cleans a path and resolves its symlinks, when it exists, so that a symlink can't
+// be used to escape an allowed root.
+func resolvePath(p string) string {
+	if resolved, err := filepath.EvalSymlinks(p); err == nil {
+		return resolved
+	}
+	return filepath.Clean(p)
+}
+
+func isUnder(p, root string) bool {
+	rel, err := filepath.Rel(root, p)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// validateNetwork checks that a server's container can be attached to the given network:
+// none, bridge, host or an existing docker network, as long as the run policy doesn't deny it.
+func validateNetwork(ctx context.Context, dockerClient docker.Client, network string, policy *runPolicy) error {
+	if err := policy.checkNetwork(network); err != nil {
+		return err
+	}
+
+	switch network {
+	case "", "none", "bridge", "host":
+		return nil
+	}
+
+	exists, err := dockerClient.NetworkExists(ctx, network)
+	if err != nil {
+		return fmt.Errorf("inspecting network %s: %w", network, err)
+	}
+	if !exists {
+		return fmt.Errorf("network %s doesn't exist", network)
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package gateway
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/docker"
+)
+
+func TestParseRunArgs(t *testing.T) {
+	parsed, err := parseRunArgs([]string{"--dns", "10.0.0.2", "--label=a=b", "-l", "site=paris", "-v/data:/data", "-it", "-eFOO=bar", "--net", "bridge", "--read-only"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []runArg{
+		{flag: "--dns", value: "10.0.0.2", text: "--dns"},
+		{flag: "--label", value: "a=b", text: "--label=a=b"},
+		{flag: "--label", value: "site=paris", text: "-l"},
+		{flag: "--volume", value: "/data:/data", text: "-v/data:/data"},
+		{flag: "--interactive", text: "-it"},
+		{flag: "--tty", text: "-it"},
+		{flag: "--env", value: "FOO=bar", text: "-eFOO=bar"},
+		{flag: "--network", value: "bridge", text: "--net"},
+		{flag: "--read-only", text: "--read-only"},
+	}, parsed)
+
+	_, err = parseRunArgs([]string{"--dns"})
+	require.ErrorContains(t, err, "missing a value")
+	_, err = parseRunArgs([]string{"alpine"})
+	require.ErrorContains(t, err, "is not a flag")
+}
+
+func TestValidateVolumeMounts(t *testing.T) {
+	root := t.TempDir()
+	project := filepath.Join(root, "project")
+	require.NoError(t, os.Mkdir(project, 0o755))
+
+	mount := func(hostPath string) []catalog.VolumeMount {
+		return []catalog.VolumeMount{{HostPath: hostPath, ContainerPath: "/workspace"}}
+	}
+
+	require.NoError(t, validateVolumeMounts(mount(project), []string{root}))
+	require.NoError(t, validateVolumeMounts(mount(root), []string{root}))
+	require.NoError(t, validateVolumeMounts(nil, nil))
+
+	require.Error(t, validateVolumeMounts(mount(project), nil))
+	require.Error(t, validateVolumeMounts(mount("/etc"), []string{root}))
+	require.Error(t, validateVolumeMounts(mount(filepath.Join(project, "..", "..")), []string{root}))
+	require.Error(t, validateVolumeMounts(mount(root+"-other"), []string{root}))
+	require.Error(t, validateVolumeMounts(mount("project"), []string{root}))
+	require.Error(t, validateVolumeMounts([]catalog.VolumeMount{{HostPath: project}}, []string{root}))
+
+	// Separators of the -v options
+	for _, mount := range []catalog.VolumeMount{
+		{HostPath: project, ContainerPath: "/workspace:rw"},
+		{HostPath: project + ":/etc", ContainerPath: "/workspace"},
+		{HostPath: project, ContainerPath: "/workspace,z"},
+		{HostPath: project + ",readonly", ContainerPath: "/workspace"},
+	} {
+		err := validateVolumeMounts([]catalog.VolumeMount{mount}, []string{root})
+		require.ErrorContains(t, err, "can't have ':' or ','", mount)
+	}
+}
+
+func TestValidateVolumeMountsSymlinkEscape(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	link := filepath.Join(root, "link")
+	require.NoError(t, os.Symlink(outside, link))
+
+	err := validateVolumeMounts([]catalog.VolumeMount{{HostPath: link, ContainerPath: "/data"}}, []string{root})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not allowed")
+}
+
+type fakeNetworkClient struct {
+	docker.Client
+	networks []string
+}
+
+func (c *fakeNetworkClient) NetworkExists(_ context.Context, name string) (bool, error) {
+	return slices.Contains(c.networks, name), nil
+}
+
+func TestValidateNetwork(t *testing.T) {
+	client := &fakeNetworkClient{networks: []string{"sidecar-db"}}
+
+	for _, network := range []string{"", "none", "bridge", "sidecar-db"} {
+		require.NoError(t, validateNetwork(t.Context(), client, network, defaultRunPolicy()), network)
+	}
+
+	err := validateNetwork(t.Context(), client, "unknown", defaultRunPolicy())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "network unknown doesn't exist")
+
+	require.Error(t, validateNetwork(t.Context(), client, "host", defaultRunPolicy()))
+	require.NoError(t, validateNetwork(t.Context(), client, "host", nil))
+}
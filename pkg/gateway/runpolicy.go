@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// runPolicy restricts the docker run settings that the registry's server entries can apply
+// to the containers. A nil policy allows everything.
+type runPolicy struct {
+	// AllowedRunArgs, when not empty, are the only flags the extraRunArgs can use.
+	AllowedRunArgs []string `yaml:"allowedRunArgs,omitempty"`
+	// DeniedRunArgs are the flags the extraRunArgs can't use.
+	DeniedRunArgs []string `yaml:"deniedRunArgs,omitempty"`
+	// AllowedCapabilities are the linux capabilities that --cap-add can add, when it's not denied.
+	AllowedCapabilities []string `yaml:"allowedCapabilities,omitempty"`
+	// AllowedVolumes, when not empty, are the host paths, with their subdirectories, and the named
+	// volumes that the catalog's volumes can mount.
+	AllowedVolumes []string `yaml:"allowedVolumes,omitempty"`
+	// DeniedUsers are the users, names or ids, that the catalog can't run the containers as.
+	DeniedUsers []string `yaml:"deniedUsers,omitempty"`
+	// DeniedNetworks are the networks the containers can't be attached to. A network mode,
+	// e.g. container, denies all its networks, e.g. container:<id>.
+	DeniedNetworks []string `yaml:"deniedNetworks,omitempty"`
+}
+
+// defaultRunPolicy is the safe baseline: only the flags known not to weaken the isolation of
+// a container, and neither the host network nor the network of another container.
+func defaultRunPolicy() *runPolicy {
+	return &runPolicy{
+		AllowedRunArgs: slices.Clone(safeRunArgs),
+		DeniedRunArgs:  slices.Clone(privilegedRunArgs),
+		DeniedNetworks: []string{"host", "container"},
+	}
+}
+
+// loadRunPolicy reads a run policy from a yaml file. The settings missing from the file keep
+// the value of the default policy.
+func loadRunPolicy(path string) (*runPolicy, error) {
+	buf, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading run policy: %w", err)
+	}
+
+	policy := defaultRunPolicy()
+	if err := yaml.Unmarshal(buf, policy); err != nil {
+		return nil, fmt.Errorf("parsing run policy %s: %w", path, err)
+	}
+
+	return policy, nil
+}
+
+// checkRunArgs rejects the first docker run arg that the policy doesn't allow. The flags are
+// compared in their long form, so -v/host:/c is checked as --volume.
+func (p *runPolicy) checkRunArgs(args []string) error {
+	if p == nil {
+		return nil
+	}
+
+	parsed, err := parseRunArgs(args)
+	if err != nil {
+		return err
+	}
+
+	allowed := normalizeRunFlags(p.AllowedRunArgs)
+	denied := normalizeRunFlags(p.DeniedRunArgs)
+	for _, arg := range parsed {
+		if len(allowed) > 0 && !slices.Contains(allowed, arg.flag) {
+			return fmt.Errorf("run arg %q is not in the allowed run args of the run policy", arg.text)
+		}
+		if slices.Contains(denied, arg.flag) {
+			return fmt.Errorf("run arg %q is denied by the run policy", arg.text)
+		}
+
+		switch arg.flag {
+		case "--cap-add":
+			if !slices.ContainsFunc(p.AllowedCapabilities, func(capability string) bool {
+				return normalizeCapability(capability) == normalizeCapability(arg.value)
+			}) {
+				return fmt.Errorf("run arg %q: capability %q is not allowed by the run policy", arg.text, arg.value)
+			}
+		case "--network":
+			if err := p.checkNetwork(arg.value); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkCatalogSettings rejects the volumes and the user, set by the catalog for a container, that
+// the policy doesn't allow. The default policy allows all of them.
+func (p *runPolicy) checkCatalogSettings(volumes []string, user string) error {
+	if p == nil {
+		return nil
+	}
+
+	for _, volume := range volumes {
+		source := volumeSource(volume)
+		if len(p.AllowedVolumes) > 0 && !slices.ContainsFunc(p.AllowedVolumes, func(allowed string) bool {
+			if filepath.IsAbs(source) && filepath.IsAbs(allowed) {
+				return isUnder(resolvePath(source), resolvePath(allowed))
+			}
+			return source == allowed
+		}) {
+			return fmt.Errorf("volume %q is not in the allowed volumes of the run policy", volume)
+		}
+	}
+
+	name, _, _ := strings.Cut(user, ":")
+	if user != "" && slices.Contains(p.DeniedUsers, name) {
+		return fmt.Errorf("user %q is denied by the run policy", user)
+	}
+
+	return nil
+}
+
+// volumeSource returns the host path or the named volume of a docker volume, e.g. /data for
+// /data:/workspace:ro. A windows drive letter is kept with the path.
+func volumeSource(volume string) string {
+	drive := ""
+	if len(volume) >= 2 && volume[1] == ':' {
+		drive, volume = volume[:2], volume[2:]
+	}
+	source, _, _ := strings.Cut(volume, ":")
+	return drive + source
+}
+
+// checkNetwork rejects the networks denied by the policy.
+func (p *runPolicy) checkNetwork(network string) error {
+	if p == nil || network == "" {
+		return nil
+	}
+
+	mode, _, _ := strings.Cut(network, ":")
+	if slices.Contains(p.DeniedNetworks, network) || slices.Contains(p.DeniedNetworks, mode) {
+		return fmt.Errorf("network %q is denied by the run policy", network)
+	}
+
+	return nil
+}
+
+// normalizeRunFlags returns the long form of the flags of a policy.
+func normalizeRunFlags(flags []string) []string {
+	normalized := make([]string, 0, len(flags))
+	for _, flag := range flags {
+		normalized = append(normalized, normalizeRunFlag(flag))
+	}
+	return normalized
+}
+
+// normalizeCapability accepts both NET_ADMIN and cap_net_admin.
+func normalizeCapability(capability string) string {
+	capability = strings.ToUpper(strings.TrimSpace(capability))
+	return strings.TrimPrefix(capability, "CAP_")
+}
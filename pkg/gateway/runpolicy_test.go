@@ -0,0 +1,128 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRunPolicy(t *testing.T) {
+	policy := defaultRunPolicy()
+
+	require.NoError(t, policy.checkRunArgs([]string{"--add-host", "db:10.0.0.1", "--tmpfs=/tmp"}))
+	require.NoError(t, policy.checkRunArgs(nil))
+
+	err := policy.checkRunArgs([]string{"--privileged"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"--privileged"`)
+
+	require.Error(t, policy.checkRunArgs([]string{"--cap-add=NET_ADMIN"}))
+	require.Error(t, policy.checkRunArgs([]string{"-v", "/:/host"}))
+	require.Error(t, policy.checkRunArgs([]string{"-v/:/host"}))
+	require.Error(t, policy.checkRunArgs([]string{"--user", "0"}))
+	require.Error(t, policy.checkRunArgs([]string{"--network=container:db"}))
+	require.Error(t, policy.checkRunArgs([]string{"--network", "host"}))
+	require.Error(t, policy.checkRunArgs([]string{"--net=host"}))
+	require.NoError(t, policy.checkRunArgs([]string{"--network", "bridge"}))
+}
+
+func TestNilRunPolicyAllowsEverything(t *testing.T) {
+	var policy *runPolicy
+
+	require.NoError(t, policy.checkRunArgs([]string{"--privileged", "--cap-add", "ALL"}))
+	require.NoError(t, policy.checkNetwork("host"))
+}
+
+func TestRunPolicyCapabilities(t *testing.T) {
+	policy := &runPolicy{AllowedCapabilities: []string{"NET_ADMIN"}}
+
+	require.NoError(t, policy.checkRunArgs([]string{"--cap-add", "NET_ADMIN"}))
+	require.NoError(t, policy.checkRunArgs([]string{"--cap-add=cap_net_admin"}))
+
+	err := policy.checkRunArgs([]string{"--cap-add", "SYS_ADMIN"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `capability "SYS_ADMIN"`)
+}
+
+func TestRunPolicyAllowedRunArgs(t *testing.T) {
+	policy := &runPolicy{AllowedRunArgs: []string{"--tmpfs", "--add-host"}}
+
+	require.NoError(t, policy.checkRunArgs([]string{"--tmpfs", "/tmp", "--add-host=db:10.0.0.1"}))
+
+	err := policy.checkRunArgs([]string{"--tmpfs", "/tmp", "--shm-size", "1g"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `"--shm-size"`)
+}
+
+func TestLoadRunPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+deniedRunArgs: [--privileged]
+allowedCapabilities: [NET_BIND_SERVICE]
+`), 0o644))
+
+	policy, err := loadRunPolicy(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"--privileged"}, policy.DeniedRunArgs)
+	assert.Equal(t, []string{"NET_BIND_SERVICE"}, policy.AllowedCapabilities)
+	// Settings missing from the file keep the default
+	assert.Equal(t, []string{"host", "container"}, policy.DeniedNetworks)
+
+	require.NoError(t, policy.checkRunArgs([]string{"--cap-add", "NET_BIND_SERVICE"}))
+	require.Error(t, policy.checkRunArgs([]string{"--privileged"}))
+}
+
+func TestLoadRunPolicyInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("deniedRunArgs: {"), 0o644))
+
+	_, err := loadRunPolicy(path)
+	require.Error(t, err)
+
+	_, err = loadRunPolicy(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
+
+func TestRunPolicyNormalizesFlags(t *testing.T) {
+	policy := &runPolicy{DeniedRunArgs: []string{"-v", "--net"}}
+
+	for _, args := range [][]string{
+		{"-v", "/:/host"},
+		{"-v/:/host"},
+		{"-v=/:/host"},
+		{"--volume=/:/host"},
+		{"-itv/:/host"},
+		{"--network", "bridge"},
+	} {
+		require.Error(t, policy.checkRunArgs(args), args)
+	}
+	require.NoError(t, policy.checkRunArgs([]string{"-l", "site=paris", "--tmpfs", "/tmp"}))
+}
+
+func TestRunPolicyCatalogSettings(t *testing.T) {
+	root := t.TempDir()
+	policy := &runPolicy{AllowedVolumes: []string{root, "cache"}, DeniedUsers: []string{"0", "root"}}
+
+	require.NoError(t, policy.checkCatalogSettings([]string{filepath.Join(root, "data") + ":/data:ro", "cache:/cache"}, "1000:1000"))
+	require.NoError(t, policy.checkCatalogSettings(nil, ""))
+
+	err := policy.checkCatalogSettings([]string{"/var/run/docker.sock:/var/run/docker.sock"}, "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/var/run/docker.sock")
+	require.Error(t, policy.checkCatalogSettings([]string{"other:/data"}, ""))
+	require.Error(t, policy.checkCatalogSettings(nil, "0"))
+	require.Error(t, policy.checkCatalogSettings(nil, "root:root"))
+
+	// The default policy keeps the catalog's settings
+	require.NoError(t, defaultRunPolicy().checkCatalogSettings([]string{"/var/run/docker.sock:/var/run/docker.sock"}, "0"))
+}
+
+func TestVolumeSource(t *testing.T) {
+	assert.Equal(t, "/data", volumeSource("/data:/workspace:ro"))
+	assert.Equal(t, "cache", volumeSource("cache:/cache"))
+	assert.Equal(t, `C:\Users\me`, volumeSource(`C:\Users\me:/workspace`))
+}
@@ -0,0 +1,53 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+// errOutsideSandbox is returned when an internal tool tries to access a file outside of --internal-tools-dir
+var errOutsideSandbox = errors.New("path is outside of the internal tools directory")
+
+// internalToolPath returns the path of a file read or written by the internal tools.
+// Without a sandbox directory, relative paths are resolved under ~/.docker/mcp/, as usual.
+// With one, every path is joined under it and paths that would escape it are rejected.
+func internalToolPath(sandboxDir, name string) (string, error) {
+	if sandboxDir == "" {
+		return config.FilePath(name)
+	}
+
+	base, err := filepath.Abs(sandboxDir)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(base, name)
+	if filepath.IsAbs(name) {
+		path = filepath.Clean(name)
+	}
+
+	rel, err := filepath.Rel(base, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is not under %s", errOutsideSandbox, name, base)
+	}
+
+	return path, nil
+}
+
+// writeInternalToolFile writes a file under the sandbox directory, or under ~/.docker/mcp/ without one.
+func writeInternalToolFile(sandboxDir, name string, content []byte, perm os.FileMode) error {
+	path, err := internalToolPath(sandboxDir, name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, perm)
+}
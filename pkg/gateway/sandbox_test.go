@@ -0,0 +1,56 @@
+package gateway
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInternalToolPath(t *testing.T) {
+	sandboxDir := t.TempDir()
+
+	path, err := internalToolPath(sandboxDir, "quotas.json")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(sandboxDir, "quotas.json"), path)
+
+	path, err = internalToolPath(sandboxDir, filepath.Join("session", "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(sandboxDir, "session", "config.yaml"), path)
+
+	path, err = internalToolPath(sandboxDir, filepath.Join(sandboxDir, "secrets.env"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(sandboxDir, "secrets.env"), path)
+
+	for _, name := range []string{"../secrets.env", "session/../../config.yaml", "/etc/passwd", ".."} {
+		_, err := internalToolPath(sandboxDir, name)
+		require.ErrorIs(t, err, errOutsideSandbox, name)
+	}
+}
+
+func TestInternalToolPathWithoutSandbox(t *testing.T) {
+	path, err := internalToolPath("", "/tmp/secrets.env")
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp/secrets.env", path)
+}
+
+func TestPersistUnderSandbox(t *testing.T) {
+	sandboxDir := t.TempDir()
+	configuration := Configuration{
+		SessionName: "tenant-a",
+		serverNames: []string{"github"},
+		config:      map[string]map[string]any{"github": {"owner": "docker"}},
+	}
+
+	require.NoError(t, configuration.Persist(sandboxDir))
+
+	for _, name := range []string{"registry.yaml", "config.yaml", "tools.yaml"} {
+		_, err := os.Stat(filepath.Join(sandboxDir, "tenant-a", name))
+		require.NoError(t, err)
+	}
+
+	configuration.SessionName = "../escape"
+	require.ErrorIs(t, configuration.Persist(sandboxDir), errOutsideSandbox)
+}
@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// gatewayStats holds the counters maintained while the gateway is running.
+// The zero value is ready to use.
+type gatewayStats struct {
+	mu             sync.Mutex
+	toolCalls      map[string]int64
+	searches       int64
+	searchDuration time.Duration
+	cacheHits      int64
+	cacheMisses    int64
+}
+
+type statsSnapshot struct {
+	TotalToolCalls         int64            `json:"total_tool_calls"`
+	ToolCalls              map[string]int64 `json:"tool_calls"`
+	Searches               int64            `json:"searches"`
+	AverageSearchLatencyMs float64          `json:"average_search_latency_ms"`
+	ToolCacheHits          int64            `json:"tool_cache_hits"`
+	ToolCacheMisses        int64            `json:"tool_cache_misses"`
+}
+
+func (s *gatewayStats) recordToolCall(toolName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.toolCalls == nil {
+		s.toolCalls = make(map[string]int64)
+	}
+	s.toolCalls[toolName]++
+}
+
+func (s *gatewayStats) recordSearch(duration time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.searches++
+	s.searchDuration += duration
+}
+
+func (s *gatewayStats) recordToolCache(hit bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hit {
+		s.cacheHits++
+	} else {
+		s.cacheMisses++
+	}
+}
+
+func (s *gatewayStats) snapshot() statsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := statsSnapshot{
+		ToolCalls:       make(map[string]int64, len(s.toolCalls)),
+		Searches:        s.searches,
+		ToolCacheHits:   s.cacheHits,
+		ToolCacheMisses: s.cacheMisses,
+	}
+	for toolName, count := range s.toolCalls {
+		snapshot.ToolCalls[toolName] = count
+		snapshot.TotalToolCalls += count
+	}
+	if s.searches > 0 {
+		snapshot.AverageSearchLatencyMs = float64(s.searchDuration.Microseconds()) / float64(s.searches) / 1000
+	}
+
+	return snapshot
+}
+
+// withToolStats counts the calls to a tool.
+func (s *gatewayStats) withToolStats(toolName string, handler mcp.ToolHandler) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.recordToolCall(toolName)
+		return handler(ctx, req)
+	}
+}
+
+// createStatsTool implements a tool reporting the counters maintained by the gateway
+func (g *Gateway) createStatsTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "stats",
+		Description: "Report the gateway's counters: total and per-tool call counts, number of mcp-find searches and their average latency, hits and misses of the cache of the cacheable tools' results.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+		},
+	}
+
+	handler := func(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return g.jsonToolResult(g.stats.snapshot(), true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("stats", handler),
+	}
+}
+
+type catalogStats struct {
+	TotalServers                int            `json:"total_servers"`
+	EnabledServers              int            `json:"enabled_servers"`
+	TotalTools                  int            `json:"total_tools"`
+	ToolsPerServer              map[string]int `json:"tools_per_server"`
+	ServersRequiringSecrets     int            `json:"servers_requiring_secrets"`
+	ServersWithSecretsSatisfied int            `json:"servers_with_secrets_satisfied"`
+}
+
+// computeCatalogStats counts the servers and tools without interacting with any container.
+// Secrets are only counted for the enabled servers since they are the only ones whose secrets are read.
+func computeCatalogStats(configuration Configuration, registrations []*ToolRegistration) catalogStats {
+	stats := catalogStats{
+		TotalServers:   len(configuration.servers),
+		EnabledServers: len(configuration.serverNames),
+		ToolsPerServer: map[string]int{},
+	}
+
+	for _, serverName := range configuration.serverNames {
+		stats.ToolsPerServer[serverName] = 0
+
+		server, found := configuration.servers[serverName]
+		if !found || len(server.Secrets) == 0 {
+			continue
+		}
+
+		stats.ServersRequiringSecrets++
+		satisfied := true
+		for _, secret := range server.Secrets {
+			if configuration.secrets[secret.Name] == "" {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			stats.ServersWithSecretsSatisfied++
+		}
+	}
+
+	// Internal tools don't belong to any server
+	for _, registration := range registrations {
+		if registration.ServerName == "" {
+			continue
+		}
+		stats.TotalTools++
+		stats.ToolsPerServer[registration.ServerName]++
+	}
+
+	return stats
+}
+
+// createCatalogStatsTool implements a tool reporting aggregate statistics about the catalog
+func (g *Gateway) createCatalogStatsTool() *ToolRegistration {
+	tool := &mcp.Tool{
+		Name:        "catalog-stats",
+		Description: "Report aggregate statistics about the catalog: total and enabled servers, total tools and tools per server, how many enabled servers require secrets and how many of those have all their secrets set.",
+		InputSchema: &jsonschema.Schema{
+			Type: "object",
+		},
+	}
+
+	handler := func(_ context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return g.jsonToolResult(computeCatalogStats(g.currentConfiguration(), g.GetToolRegistrationsSorted()), true)
+	}
+
+	return &ToolRegistration{
+		Tool:    tool,
+		Handler: withToolTelemetry("catalog-stats", handler),
+	}
+}
@@ -0,0 +1,76 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+func TestGatewayStats(t *testing.T) {
+	var stats gatewayStats
+
+	handler := stats.withToolStats("search", func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{}, nil
+	})
+	for range 3 {
+		_, err := handler(t.Context(), &mcp.CallToolRequest{})
+		require.NoError(t, err)
+	}
+	stats.recordToolCall("mcp-find")
+	stats.recordSearch(10 * time.Millisecond)
+	stats.recordSearch(20 * time.Millisecond)
+
+	snapshot := stats.snapshot()
+	assert.Equal(t, int64(4), snapshot.TotalToolCalls)
+	assert.Equal(t, map[string]int64{"search": 3, "mcp-find": 1}, snapshot.ToolCalls)
+	assert.Equal(t, int64(2), snapshot.Searches)
+	assert.InDelta(t, 15.0, snapshot.AverageSearchLatencyMs, 0.001)
+}
+
+func TestGatewayStatsEmpty(t *testing.T) {
+	var stats gatewayStats
+
+	snapshot := stats.snapshot()
+	assert.Zero(t, snapshot.TotalToolCalls)
+	assert.Empty(t, snapshot.ToolCalls)
+	assert.Zero(t, snapshot.AverageSearchLatencyMs)
+}
+
+func TestComputeCatalogStats(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"github", "slack", "fetch"},
+		servers: map[string]catalog.Server{
+			"github": {Secrets: []catalog.Secret{{Name: "github.token"}}},
+			"slack":  {Secrets: []catalog.Secret{{Name: "slack.token"}, {Name: "slack.team"}}},
+			"fetch":  {},
+			"notion": {Secrets: []catalog.Secret{{Name: "notion.token"}}},
+		},
+		secrets: map[string]string{
+			"github.token": "ghp_xxx",
+			"slack.token":  "xoxb",
+		},
+	}
+	registrations := []*ToolRegistration{
+		{ServerName: "github", Tool: &mcp.Tool{Name: "get_me"}},
+		{ServerName: "github", Tool: &mcp.Tool{Name: "search_issues"}},
+		{ServerName: "fetch", Tool: &mcp.Tool{Name: "fetch"}},
+		{Tool: &mcp.Tool{Name: "mcp-find"}},
+	}
+
+	stats := computeCatalogStats(configuration, registrations)
+
+	assert.Equal(t, catalogStats{
+		TotalServers:                4,
+		EnabledServers:              3,
+		TotalTools:                  3,
+		ToolsPerServer:              map[string]int{"github": 2, "slack": 0, "fetch": 1},
+		ServersRequiringSecrets:     2,
+		ServersWithSecretsSatisfied: 1,
+	}, stats)
+}
@@ -0,0 +1,29 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// withToolTimeout bounds each call to a tool with a deadline. A zero timeout means no deadline.
+func withToolTimeout(toolName string, timeout time.Duration, handler mcp.ToolHandler) mcp.ToolHandler {
+	if timeout <= 0 {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := handler(ctx, req)
+		if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("tool %q timed out after %s", toolName, timeout)
+		}
+
+		return result, err
+	}
+}
@@ -0,0 +1,46 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithToolTimeout(t *testing.T) {
+	slow := func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	_, err := withToolTimeout("slow", 10*time.Millisecond, slow)(t.Context(), &mcp.CallToolRequest{})
+	require.Error(t, err)
+	assert.Equal(t, `tool "slow" timed out after 10ms`, err.Error())
+}
+
+func TestWithToolTimeoutFastTool(t *testing.T) {
+	fast := func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "done"}}}, nil
+	}
+
+	result, err := withToolTimeout("fast", time.Second, fast)(t.Context(), &mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "done", result.Content[0].(*mcp.TextContent).Text)
+}
+
+func TestWithToolTimeoutDisabled(t *testing.T) {
+	called := false
+	handler := func(ctx context.Context, _ *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		called = true
+		_, hasDeadline := ctx.Deadline()
+		assert.False(t, hasDeadline)
+		return &mcp.CallToolResult{}, nil
+	}
+
+	_, err := withToolTimeout("tool", 0, handler)(t.Context(), &mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, called)
+}
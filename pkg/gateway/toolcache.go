@@ -0,0 +1,171 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxToolResultCacheEntries is how many results the tool result cache holds at most
+const maxToolResultCacheEntries = 1000
+
+// toolResultCache caches the results of the tools marked as cacheable in the registry,
+// keyed on the tool name and the canonicalized arguments. Those tools must be pure
+// functions of their arguments. Errors are never cached.
+//
+// The results are stored serialized, so that every caller gets a copy of its own.
+// When the cache is full, the entry closest to expiring is evicted.
+type toolResultCache struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	stats      *gatewayStats
+
+	mu      sync.Mutex
+	entries map[string]toolResultCacheEntry
+}
+
+type toolResultCacheEntry struct {
+	result  []byte
+	expires time.Time
+}
+
+func newToolResultCache(ttl time.Duration, stats *gatewayStats) *toolResultCache {
+	return &toolResultCache{
+		ttl:        ttl,
+		maxEntries: maxToolResultCacheEntries,
+		now:        time.Now,
+		stats:      stats,
+		entries:    map[string]toolResultCacheEntry{},
+	}
+}
+
+// toolResultCacheKey canonicalizes the arguments, so that the order of the keys or
+// the spacing don't matter. Arguments that aren't valid JSON are used as is.
+func toolResultCacheKey(toolName string, arguments json.RawMessage) string {
+	decoder := json.NewDecoder(bytes.NewReader(arguments))
+	decoder.UseNumber()
+
+	var decoded any
+	if err := decoder.Decode(&decoded); err == nil {
+		if canonical, err := json.Marshal(decoded); err == nil {
+			arguments = canonical
+		}
+	}
+
+	return toolName + "\n" + string(arguments)
+}
+
+func (c *toolResultCache) get(key string) (*mcp.CallToolResult, bool) {
+	c.mu.Lock()
+	entry, found := c.entries[key]
+	if found && !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		found = false
+	}
+	c.mu.Unlock()
+
+	if !found {
+		return nil, false
+	}
+
+	var result mcp.CallToolResult
+	if err := json.Unmarshal(entry.result, &result); err != nil {
+		return nil, false
+	}
+
+	return &result, true
+}
+
+func (c *toolResultCache) put(key string, result *mcp.CallToolResult) {
+	buf, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	if _, found := c.entries[key]; !found && c.maxEntries > 0 && len(c.entries) >= c.maxEntries {
+		c.evictOldest()
+	}
+
+	c.entries[key] = toolResultCacheEntry{
+		result:  buf,
+		expires: now.Add(c.ttl),
+	}
+}
+
+// evictOldest evicts the entry closest to expiring. The lock must be held.
+func (c *toolResultCache) evictOldest() {
+	var (
+		oldestKey string
+		oldest    time.Time
+	)
+	for k, entry := range c.entries {
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = k, entry.expires
+		}
+	}
+	delete(c.entries, oldestKey)
+}
+
+// clear evicts all the entries and returns how many there were.
+func (c *toolResultCache) clear() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	evicted := len(c.entries)
+	c.entries = map[string]toolResultCacheEntry{}
+
+	return evicted
+}
+
+// withToolCache short-circuits the calls to a tool whose result is cached.
+func (c *toolResultCache) withToolCache(toolName string, handler mcp.ToolHandler) mcp.ToolHandler {
+	if c == nil || c.ttl <= 0 {
+		return handler
+	}
+
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var arguments json.RawMessage
+		if req.Params != nil {
+			arguments = req.Params.Arguments
+		}
+		key := toolResultCacheKey(toolName, arguments)
+
+		if result, found := c.get(key); found {
+			c.stats.recordToolCache(true)
+			return result, nil
+		}
+		c.stats.recordToolCache(false)
+
+		result, err := handler(ctx, req)
+		if err == nil && result != nil && !result.IsError {
+			c.put(key, result)
+		}
+
+		return result, err
+	}
+}
+
+// isToolCacheable tells whether the server's registry entry marks a tool as cacheable.
+func isToolCacheable(configuration Configuration, serverName, toolName string) bool {
+	return slices.Contains(configuration.registry[serverName].CacheableTools, toolName)
+}
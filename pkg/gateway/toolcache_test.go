@@ -0,0 +1,148 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+func TestToolResultCacheKey(t *testing.T) {
+	assert.Equal(t, toolResultCacheKey("search", json.RawMessage(`{"b":1, "a":"x"}`)), toolResultCacheKey("search", json.RawMessage(`{"a":"x","b":1}`)))
+	assert.NotEqual(t, toolResultCacheKey("search", json.RawMessage(`{"a":"x"}`)), toolResultCacheKey("search", json.RawMessage(`{"a":"y"}`)))
+	assert.NotEqual(t, toolResultCacheKey("search", json.RawMessage(`{"a":"x"}`)), toolResultCacheKey("fetch", json.RawMessage(`{"a":"x"}`)))
+	assert.NotEqual(t, toolResultCacheKey("search", json.RawMessage(`{"n":12345678901234567890}`)), toolResultCacheKey("search", json.RawMessage(`{"n":12345678901234567891}`)))
+}
+
+func countingHandler(calls *int, isError bool) mcp.ToolHandler {
+	return func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		*calls++
+		return &mcp.CallToolResult{IsError: isError}, nil
+	}
+}
+
+func callWithArguments(t *testing.T, handler mcp.ToolHandler, arguments string) {
+	t.Helper()
+
+	_, err := handler(t.Context(), &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Arguments: json.RawMessage(arguments)}})
+	require.NoError(t, err)
+}
+
+func TestToolResultCache(t *testing.T) {
+	now := time.Now()
+	var stats gatewayStats
+	cache := newToolResultCache(time.Minute, &stats)
+	cache.now = func() time.Time { return now }
+
+	var calls int
+	handler := cache.withToolCache("search", countingHandler(&calls, false))
+
+	callWithArguments(t, handler, `{"query":"docker","limit":5}`)
+	callWithArguments(t, handler, `{"limit":5,"query":"docker"}`)
+	assert.Equal(t, 1, calls)
+
+	callWithArguments(t, handler, `{"query":"mcp"}`)
+	assert.Equal(t, 2, calls)
+
+	now = now.Add(time.Minute)
+	callWithArguments(t, handler, `{"query":"docker","limit":5}`)
+	assert.Equal(t, 3, calls)
+
+	snapshot := stats.snapshot()
+	assert.Equal(t, int64(1), snapshot.ToolCacheHits)
+	assert.Equal(t, int64(3), snapshot.ToolCacheMisses)
+
+	assert.Equal(t, 1, cache.clear())
+	callWithArguments(t, handler, `{"query":"docker","limit":5}`)
+	assert.Equal(t, 4, calls)
+}
+
+func TestToolResultCacheSkipsErrors(t *testing.T) {
+	var stats gatewayStats
+	cache := newToolResultCache(time.Minute, &stats)
+
+	var calls int
+	handler := cache.withToolCache("search", countingHandler(&calls, true))
+
+	callWithArguments(t, handler, `{}`)
+	callWithArguments(t, handler, `{}`)
+	assert.Equal(t, 2, calls)
+}
+
+func TestToolResultCacheDisabled(t *testing.T) {
+	var cache *toolResultCache
+
+	var calls int
+	handler := cache.withToolCache("search", countingHandler(&calls, false))
+
+	callWithArguments(t, handler, `{}`)
+	callWithArguments(t, handler, `{}`)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 0, cache.clear())
+}
+
+func TestToolResultCacheReturnsCopies(t *testing.T) {
+	var stats gatewayStats
+	cache := newToolResultCache(time.Minute, &stats)
+
+	handler := cache.withToolCache("search", func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "result"}}}, nil
+	})
+	req := &mcp.CallToolRequest{Params: &mcp.CallToolParamsRaw{Arguments: json.RawMessage(`{}`)}}
+
+	first, err := handler(t.Context(), req)
+	require.NoError(t, err)
+	first.Content[0].(*mcp.TextContent).Text = "changed by the first caller"
+
+	second, err := handler(t.Context(), req)
+	require.NoError(t, err)
+	second.Content = nil
+
+	third, err := handler(t.Context(), req)
+	require.NoError(t, err)
+	require.Len(t, third.Content, 1)
+	assert.Equal(t, "result", third.Content[0].(*mcp.TextContent).Text)
+	assert.Equal(t, int64(2), stats.snapshot().ToolCacheHits)
+}
+
+func TestToolResultCacheMaxEntries(t *testing.T) {
+	now := time.Now()
+	var stats gatewayStats
+	cache := newToolResultCache(time.Minute, &stats)
+	cache.maxEntries = 2
+	cache.now = func() time.Time { return now }
+
+	var calls int
+	handler := cache.withToolCache("search", countingHandler(&calls, false))
+
+	for _, query := range []string{"a", "b", "c"} {
+		callWithArguments(t, handler, `{"query":"`+query+`"}`)
+		now = now.Add(time.Second)
+	}
+	assert.Len(t, cache.entries, 2)
+
+	// The oldest result was evicted, the others are still cached
+	callWithArguments(t, handler, `{"query":"c"}`)
+	callWithArguments(t, handler, `{"query":"b"}`)
+	assert.Equal(t, 3, calls)
+	callWithArguments(t, handler, `{"query":"a"}`)
+	assert.Equal(t, 4, calls)
+}
+
+func TestIsToolCacheable(t *testing.T) {
+	configuration := Configuration{
+		registry: map[string]config.Tile{
+			"search": {CacheableTools: []string{"lookup"}},
+		},
+	}
+
+	assert.True(t, isToolCacheable(configuration, "search", "lookup"))
+	assert.False(t, isToolCacheable(configuration, "search", "post"))
+	assert.False(t, isToolCacheable(configuration, "other", "lookup"))
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -27,20 +28,26 @@ func (g *Gateway) startSseServer(ctx context.Context, ln net.Listener) error {
 	}, nil)
 	mux.Handle("/sse", originSecurityHandler(sseHandler))
 
+	// Compress large responses
+	var handler http.Handler = gzipHandler(mux)
+
 	// Wrap with authentication middleware
-	var handler http.Handler = mux
 	if g.authToken != "" {
-		handler = authenticationMiddleware(g.authToken, mux)
+		handler = authenticationMiddleware(g.authToken, handler)
 	}
 
 	httpServer := &http.Server{
 		Handler: handler,
 	}
+	g.onClose(httpServer.Close)
 	go func() {
 		<-ctx.Done()
 		ln.Close()
 	}()
-	return httpServer.Serve(ln)
+	if err := httpServer.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 func (g *Gateway) startStreamingServer(ctx context.Context, ln net.Listener) error {
@@ -50,23 +57,29 @@ func (g *Gateway) startStreamingServer(ctx context.Context, ln net.Listener) err
 	streamHandler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
 		return g.mcpServer
 	}, nil)
-	mux.Handle("/mcp", originSecurityHandler(streamHandler))
+	mux.Handle("/mcp", originSecurityHandler(batchHandler(streamHandler)))
+
+	// Compress large responses
+	var handler http.Handler = gzipHandler(mux)
 
 	// Wrap with authentication middleware
-	var handler http.Handler = mux
 	if g.authToken != "" {
-		handler = authenticationMiddleware(g.authToken, mux)
+		handler = authenticationMiddleware(g.authToken, handler)
 	}
 
 	httpServer := &http.Server{
 		Handler: handler,
 	}
 
+	g.onClose(httpServer.Close)
 	go func() {
 		<-ctx.Done()
 		ln.Close()
 	}()
-	return httpServer.Serve(ln)
+	if err := httpServer.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 func redirectHandler(target string) http.HandlerFunc {
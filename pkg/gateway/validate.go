@@ -0,0 +1,179 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+)
+
+var (
+	validSecretName = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	validEnvName    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+)
+
+// ValidationIssue is a problem found while statically validating the catalog and the registry.
+type ValidationIssue struct {
+	Server  string `json:"server,omitempty"`
+	Message string `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	if i.Server == "" {
+		return i.Message
+	}
+	return i.Server + ": " + i.Message
+}
+
+// Validate reads the configuration and statically checks the catalog and the registry:
+// image references, config schemas, secret names and tool name collisions.
+// It never pulls images nor starts servers.
+func (g *Gateway) Validate(ctx context.Context) []ValidationIssue {
+	configuration, _, stopConfigWatcher, err := g.configurator.Read(ctx)
+	if err != nil {
+		return []ValidationIssue{{Message: fmt.Sprintf("reading configuration: %v", err)}}
+	}
+	defer func() { _ = stopConfigWatcher() }()
+
+	return validateConfiguration(configuration, g.ToolNamePrefix)
+}
+
+// validateConfiguration returns the issues found in the whole catalog, plus the enabled servers missing from it.
+func validateConfiguration(configuration Configuration, byServerName bool) []ValidationIssue {
+	var issues []ValidationIssue
+
+	for _, serverName := range configuration.serverNames {
+		if _, found := configuration.servers[serverName]; !found {
+			issues = append(issues, ValidationIssue{Server: serverName, Message: "enabled in the registry but not found in the catalog"})
+		}
+	}
+
+	serverNames := make([]string, 0, len(configuration.servers))
+	for serverName := range configuration.servers {
+		serverNames = append(serverNames, serverName)
+	}
+	slices.Sort(serverNames)
+
+	for _, serverName := range serverNames {
+		server := configuration.servers[serverName]
+		for _, message := range validateServer(server, configuration.config[serverName]) {
+			issues = append(issues, ValidationIssue{Server: serverName, Message: message})
+		}
+		for _, memberName := range server.Members {
+			if member, found := configuration.servers[memberName]; !found || member.IsBundle() {
+				issues = append(issues, ValidationIssue{Server: serverName, Message: fmt.Sprintf("bundle member %s is not a server of the catalog", memberName)})
+			}
+		}
+	}
+
+	// Tools declared by the catalog, exposed by the enabled servers.
+	var tools []ToolRegistration
+	for _, serverName := range configuration.serverNames {
+		server, found := configuration.servers[serverName]
+		if !found {
+			continue
+		}
+
+		prefix := toolNamePrefix(configuration, serverName, server, byServerName)
+
+		for _, tool := range server.Tools {
+			tools = append(tools, ToolRegistration{
+				ServerName: serverName,
+				Tool:       &mcp.Tool{Name: prefixToolName(prefix, tool.Name)},
+			})
+		}
+	}
+	for _, collision := range findToolCollisions(tools) {
+		issues = append(issues, ValidationIssue{
+			Message: fmt.Sprintf("tool %q is declared by more than one server: %s", collision.ToolName, strings.Join(collision.ServerNames, ", ")),
+		})
+	}
+
+	// Startup dependencies between the enabled servers
+	for _, serverName := range configuration.serverNames {
+		for _, dependency := range configuration.registry[serverName].DependsOn {
+			if !slices.Contains(configuration.serverNames, dependency) {
+				issues = append(issues, ValidationIssue{Server: serverName, Message: fmt.Sprintf("depends on %s, which is not enabled", dependency)})
+			}
+		}
+	}
+	if _, err := startupOrder(configuration.serverNames, configuration.registry); err != nil {
+		issues = append(issues, ValidationIssue{Message: err.Error()})
+	}
+
+	return issues
+}
+
+// validateServer statically checks a catalog entry and the config values set for it.
+func validateServer(server catalog.Server, values map[string]any) []string {
+	var messages []string
+
+	switch {
+	case server.IsBundle():
+		if len(server.Members) == 0 {
+			messages = append(messages, "bundle without members")
+		}
+	case server.Type == "remote" || server.Remote.URL != "" || server.SSEEndpoint != "":
+	case server.Image != "":
+		if _, err := reference.ParseNormalizedNamed(server.Image); err != nil {
+			messages = append(messages, fmt.Sprintf("invalid image reference %q: %v", server.Image, err))
+		}
+	case len(server.Tools) == 0:
+		messages = append(messages, "no image nor remote URL")
+	}
+
+	// Only the syntax is checked, not whether this gateway is recent enough.
+	if err := checkGatewayVersion(server.MinGatewayVersion, ""); err != nil {
+		messages = append(messages, err.Error())
+	}
+
+	for _, tool := range server.Tools {
+		if tool.Container.Image == "" {
+			continue
+		}
+		if _, err := reference.ParseNormalizedNamed(tool.Container.Image); err != nil {
+			messages = append(messages, fmt.Sprintf("tool %s: invalid image reference %q: %v", tool.Name, tool.Container.Image, err))
+		}
+	}
+
+	for _, secret := range server.Secrets {
+		if !validSecretName.MatchString(secret.Name) {
+			messages = append(messages, fmt.Sprintf("invalid secret name %q", secret.Name))
+		}
+		if secret.Env != "" && !validEnvName.MatchString(secret.Env) {
+			messages = append(messages, fmt.Sprintf("secret %s: invalid environment variable name %q", secret.Name, secret.Env))
+		}
+	}
+
+	for i, configItem := range server.Config {
+		schemaMap, ok := configItem.(map[string]any)
+		if !ok {
+			messages = append(messages, fmt.Sprintf("config schema #%d is not an object", i+1))
+			continue
+		}
+		if properties, found := schemaMap["properties"]; found {
+			if _, ok := properties.(map[string]any); !ok {
+				messages = append(messages, fmt.Sprintf("config schema #%d: properties is not an object", i+1))
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		if configPropertySchema(server, key) == nil {
+			messages = append(messages, fmt.Sprintf("config key %q is not declared by the server's config schema", key))
+		}
+	}
+
+	return messages
+}
@@ -0,0 +1,84 @@
+package gateway
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/docker/mcp-gateway/pkg/catalog"
+	"github.com/docker/mcp-gateway/pkg/config"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"github", "gitlab", "missing"},
+		servers: map[string]catalog.Server{
+			"github": {
+				Image:   "mcp/github:latest",
+				Secrets: []catalog.Secret{{Name: "github.token", Env: "GITHUB_TOKEN"}},
+				Tools:   []catalog.Tool{{Name: "search"}},
+				Config: []any{map[string]any{
+					"properties": map[string]any{"owner": map[string]any{"type": "string"}},
+				}},
+			},
+			"gitlab": {
+				Image:   "mcp/GitLab",
+				Secrets: []catalog.Secret{{Name: "gitlab token", Env: "GITLAB-TOKEN"}},
+				Tools:   []catalog.Tool{{Name: "search"}},
+				Config:  []any{"not a schema"},
+			},
+			"notion": {},
+			"remote": {Type: "remote", Remote: catalog.Remote{URL: "https://example.com/mcp"}},
+		},
+		config: map[string]map[string]any{
+			"github": {"owner": "docker", "repo": "mcp-gateway"},
+		},
+	}
+
+	issues := validateConfiguration(configuration, false)
+
+	assert.Equal(t, []ValidationIssue{
+		{Server: "missing", Message: "enabled in the registry but not found in the catalog"},
+		{Server: "github", Message: `config key "repo" is not declared by the server's config schema`},
+		{Server: "gitlab", Message: `invalid image reference "mcp/GitLab": invalid reference format: repository name (mcp/GitLab) must be lowercase`},
+		{Server: "gitlab", Message: `invalid secret name "gitlab token"`},
+		{Server: "gitlab", Message: `secret gitlab token: invalid environment variable name "GITLAB-TOKEN"`},
+		{Server: "gitlab", Message: "config schema #1 is not an object"},
+		{Server: "notion", Message: "no image nor remote URL"},
+		{Message: `tool "search" is declared by more than one server: github, gitlab`},
+	}, issues)
+}
+
+func TestValidateConfigurationToolPrefixes(t *testing.T) {
+	configuration := Configuration{
+		serverNames: []string{"github", "gitlab"},
+		servers: map[string]catalog.Server{
+			"github": {Image: "mcp/github", Tools: []catalog.Tool{{Name: "search"}}},
+			"gitlab": {Image: "mcp/gitlab", Tools: []catalog.Tool{{Name: "search"}}},
+		},
+		registry: map[string]config.Tile{
+			"gitlab": {Prefix: "gl"},
+		},
+	}
+
+	assert.Empty(t, validateConfiguration(configuration, false))
+	assert.Empty(t, validateConfiguration(configuration, true))
+}
+
+func TestValidateConfigurationBundles(t *testing.T) {
+	configuration := Configuration{
+		servers: map[string]catalog.Server{
+			"postgres":   {Image: "mcp/postgres"},
+			"data-stack": {Type: "bundle", Members: []string{"postgres", "redis", "empty"}},
+			"empty":      {Type: "bundle"},
+		},
+	}
+
+	issues := validateConfiguration(configuration, false)
+
+	assert.Equal(t, []ValidationIssue{
+		{Server: "data-stack", Message: "bundle member redis is not a server of the catalog"},
+		{Server: "data-stack", Message: "bundle member empty is not a server of the catalog"},
+		{Server: "empty", Message: "bundle without members"},
+	}, issues)
+}
@@ -5,10 +5,27 @@ import (
 	"io"
 	"os"
 	"strings"
+	"sync/atomic"
+)
+
+// Level is the severity of a log message
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
 )
 
 var logWriter io.Writer = os.Stderr
 
+var minLevel atomic.Int32
+
+func init() {
+	minLevel.Store(int32(LevelInfo))
+}
+
 // SetLogWriter sets the log output destination
 func SetLogWriter(w io.Writer) {
 	if w != nil {
@@ -16,15 +33,73 @@ func SetLogWriter(w io.Writer) {
 	}
 }
 
-// Log prints a message to the log output
+// SetLevel sets the minimum level of the messages that are printed
+func SetLevel(level Level) {
+	minLevel.Store(int32(level))
+}
+
+// ParseLevel parses a level name: debug, info, warn or error
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug":
+		return LevelDebug, nil
+	case "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	default:
+		return LevelInfo, fmt.Errorf("invalid log level %q: expected debug, info, warn or error", name)
+	}
+}
+
+func enabled(level Level) bool {
+	return level >= Level(minLevel.Load())
+}
+
+// Log prints a message to the log output, at the info level
 func Log(a ...any) {
-	_, _ = fmt.Fprintln(logWriter, a...)
+	Info(a...)
 }
 
-// Logf prints a formatted message to the log output
+// Logf prints a formatted message to the log output, at the info level
 func Logf(format string, a ...any) {
+	if !enabled(LevelInfo) {
+		return
+	}
 	if !strings.HasSuffix(format, "\n") {
 		format += "\n"
 	}
 	_, _ = fmt.Fprintf(logWriter, format, a...)
 }
+
+// Debug prints a message only useful when troubleshooting
+func Debug(a ...any) {
+	logAt(LevelDebug, "", a...)
+}
+
+// Info prints an informational message
+func Info(a ...any) {
+	logAt(LevelInfo, "", a...)
+}
+
+// Warn prints a message about something that went wrong but didn't prevent the gateway from working
+func Warn(a ...any) {
+	logAt(LevelWarn, "Warning:", a...)
+}
+
+// Error prints a message about a failure
+func Error(a ...any) {
+	logAt(LevelError, "Error:", a...)
+}
+
+func logAt(level Level, prefix string, a ...any) {
+	if !enabled(level) {
+		return
+	}
+	if prefix != "" {
+		a = append([]any{prefix}, a...)
+	}
+	_, _ = fmt.Fprintln(logWriter, a...)
+}
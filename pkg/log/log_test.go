@@ -0,0 +1,49 @@
+package log
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	SetLogWriter(&buf)
+	t.Cleanup(func() {
+		SetLogWriter(os.Stderr)
+		SetLevel(LevelInfo)
+	})
+
+	Debug("debug message")
+	Log("info message")
+	Warn("warn message")
+	assert.Equal(t, "info message\nWarning: warn message\n", buf.String())
+
+	buf.Reset()
+	SetLevel(LevelWarn)
+	Log("info message")
+	Logf("info %s", "message")
+	Error("error message")
+	assert.Equal(t, "Error: error message\n", buf.String())
+
+	buf.Reset()
+	SetLevel(LevelDebug)
+	Debug("debug message")
+	assert.Equal(t, "debug message\n", buf.String())
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("WARN")
+	require.NoError(t, err)
+	assert.Equal(t, LevelWarn, level)
+
+	level, err = ParseLevel("debug")
+	require.NoError(t, err)
+	assert.Equal(t, LevelDebug, level)
+
+	_, err = ParseLevel("verbose")
+	require.Error(t, err)
+}
@@ -655,6 +655,10 @@ func (m *mockDockerClient) ConnectNetwork(_ context.Context, _ string, _ string,
 	return nil
 }
 
+func (m *mockDockerClient) NetworkExists(_ context.Context, _ string) (bool, error) {
+	return true, nil
+}
+
 func (m *mockDockerClient) InspectVolume(ctx context.Context, name string) (volume.Volume, error) {
 	if m.inspectVolumeFunc != nil {
 		return m.inspectVolumeFunc(ctx, name)
@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/moby/term"
+)
+
+// IsTerminal returns true if the file is attached to a terminal.
+func IsTerminal(f *os.File) bool {
+	_, isTerminal := term.GetFdInfo(f)
+	return isTerminal
+}
+
+// ReadPassword prompts on stderr and reads a line from the stdin terminal without echoing it.
+func ReadPassword(prompt string) (string, error) {
+	fd, isTerminal := term.GetFdInfo(os.Stdin)
+	if !isTerminal {
+		return "", fmt.Errorf("stdin is not a terminal")
+	}
+
+	state, err := term.SaveState(fd)
+	if err != nil {
+		return "", err
+	}
+	if err := term.DisableEcho(fd, state); err != nil {
+		return "", err
+	}
+	defer func() { _ = term.RestoreTerminal(fd, state) }()
+
+	fmt.Fprint(os.Stderr, prompt)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	fmt.Fprintln(os.Stderr)
+	if err != nil && line == "" {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}